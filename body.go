@@ -39,7 +39,10 @@ func (b *bodyWriter) Write(p []byte) (n int, err error) {
 		return 0, fmt.Errorf("body is not writable, %w", ErrOperationNotPermitted)
 	}
 
-	err = b.buffer.Set(p)
+	if err = b.buffer.Set(p); err != nil {
+		return 0, err
+	}
+
 	n = b.buffer.Len()
 
 	b.resetContentLength()
@@ -51,7 +54,10 @@ func (b *bodyWriter) WriteString(s string) (n int, err error) {
 		return 0, fmt.Errorf("body is not writable, %w", ErrOperationNotPermitted)
 	}
 
-	err = b.buffer.SetString(s)
+	if err = b.buffer.SetString(s); err != nil {
+		return 0, err
+	}
+
 	n = b.buffer.Len()
 
 	b.resetContentLength()
@@ -84,3 +90,86 @@ func (b *bodyWriter) resetContentLength() {
 		b.header.Set(HeaderContentLength, strconv.Itoa(b.buffer.Len()))
 	}
 }
+
+var _ Body = &chunkWriter{}
+
+// chunkWriter represents a single body chunk during the body streaming mode.
+// Unlike bodyWriter, it reads the underlying buffer on every access, so that changes made by the preceding handlers are visible.
+// It also never touches the Content-Length header, since it has been removed during the header phase.
+type chunkWriter struct {
+	writable bool
+	buffer   api.BufferInstance
+}
+
+func newChunkBody(buffer api.BufferInstance, writable bool) Body {
+	return &chunkWriter{
+		writable: writable,
+		buffer:   buffer,
+	}
+}
+
+func (b *chunkWriter) Write(p []byte) (n int, err error) {
+	if !b.writable {
+		return 0, fmt.Errorf("body is not writable, %w", ErrOperationNotPermitted)
+	}
+
+	if err = b.buffer.Set(p); err != nil {
+		return 0, err
+	}
+
+	n = b.buffer.Len()
+	return
+}
+
+func (b *chunkWriter) WriteString(s string) (n int, err error) {
+	if !b.writable {
+		return 0, fmt.Errorf("body is not writable, %w", ErrOperationNotPermitted)
+	}
+
+	if err = b.buffer.SetString(s); err != nil {
+		return 0, err
+	}
+
+	n = b.buffer.Len()
+	return
+}
+
+func (b *chunkWriter) String() string {
+	return string(b.Bytes())
+}
+
+func (b *chunkWriter) Bytes() []byte {
+	if b.buffer == nil {
+		return nil
+	}
+
+	return b.buffer.Bytes()
+}
+
+var _ api.BufferInstance = detachedBuffer{}
+
+// errDetachedBuffer is returned when the body is modified after the Envoy buffer has been detached.
+var errDetachedBuffer = fmt.Errorf("body is no longer writable once the trailers arrive, %w", ErrOperationNotPermitted)
+
+// detachedBuffer stands in for the Envoy buffer when the buffered body is flushed on the trailer phases,
+// since Envoy calls the trailer phases without the end of stream data phase while waiting for data.
+// The body is buffered at the Go side, hence the buffer is empty and it refuses any modification.
+type detachedBuffer struct{}
+
+func (detachedBuffer) Write(p []byte) (int, error)       { return 0, errDetachedBuffer }
+func (detachedBuffer) WriteString(s string) (int, error) { return 0, errDetachedBuffer }
+func (detachedBuffer) WriteByte(p byte) error            { return errDetachedBuffer }
+func (detachedBuffer) WriteUint16(p uint16) error        { return errDetachedBuffer }
+func (detachedBuffer) WriteUint32(p uint32) error        { return errDetachedBuffer }
+func (detachedBuffer) WriteUint64(p uint64) error        { return errDetachedBuffer }
+func (detachedBuffer) Bytes() []byte                     { return nil }
+func (detachedBuffer) Drain(offset int)                  {}
+func (detachedBuffer) Len() int                          { return 0 }
+func (detachedBuffer) Reset()                            {}
+func (detachedBuffer) String() string                    { return "" }
+func (detachedBuffer) Append(data []byte) error          { return errDetachedBuffer }
+func (detachedBuffer) Prepend(data []byte) error         { return errDetachedBuffer }
+func (detachedBuffer) AppendString(s string) error       { return errDetachedBuffer }
+func (detachedBuffer) PrependString(s string) error      { return errDetachedBuffer }
+func (detachedBuffer) Set(data []byte) error             { return errDetachedBuffer }
+func (detachedBuffer) SetString(s string) error          { return errDetachedBuffer }
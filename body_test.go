@@ -1,6 +1,7 @@
 package gonvoy
 
 import (
+	"errors"
 	"strconv"
 	"testing"
 
@@ -119,3 +120,66 @@ func TestBody_WriteString(t *testing.T) {
 		assert.Zero(t, n)
 	})
 }
+
+func TestChunkBody_Write(t *testing.T) {
+	t.Run("chunk is writable, returns no error", func(t *testing.T) {
+		bufferMock := mock_envoy.NewBufferInstance(t)
+		bufferMock.EXPECT().Set([]byte("lorem")).Return(nil)
+		bufferMock.EXPECT().SetString("ipsum").Return(nil)
+		bufferMock.EXPECT().Len().Return(5)
+
+		chunk := newChunkBody(bufferMock, true)
+
+		n, err := chunk.Write([]byte("lorem"))
+		assert.NoError(t, err)
+		assert.Equal(t, 5, n)
+
+		n, err = chunk.WriteString("ipsum")
+		assert.NoError(t, err)
+		assert.Equal(t, 5, n)
+	})
+
+	t.Run("buffer fails to set, returns no written bytes", func(t *testing.T) {
+		setErr := errors.New("set failed")
+
+		bufferMock := mock_envoy.NewBufferInstance(t)
+		bufferMock.EXPECT().Set([]byte("lorem")).Return(setErr)
+		bufferMock.EXPECT().SetString("ipsum").Return(setErr)
+
+		chunk := newChunkBody(bufferMock, true)
+
+		n, err := chunk.Write([]byte("lorem"))
+		assert.ErrorIs(t, err, setErr)
+		assert.Zero(t, n)
+
+		n, err = chunk.WriteString("ipsum")
+		assert.ErrorIs(t, err, setErr)
+		assert.Zero(t, n)
+	})
+
+	t.Run("chunk is not writable, returns an error", func(t *testing.T) {
+		chunk := newChunkBody(mock_envoy.NewBufferInstance(t), false)
+
+		n, err := chunk.WriteString("ipsum")
+		assert.ErrorIs(t, err, ErrOperationNotPermitted)
+		assert.Zero(t, n)
+	})
+}
+
+func TestBody_DetachedBuffer(t *testing.T) {
+	headerMock := mock_envoy.NewRequestHeaderMap(t)
+
+	bw := &bodyWriter{
+		writable: true,
+		buffer:   detachedBuffer{},
+		bytes:    []byte("lorem_ipsum"),
+		header:   headerMock,
+	}
+
+	assert.Equal(t, "lorem_ipsum", bw.String())
+
+	n, err := bw.WriteString("dolor_sit_amet")
+	assert.ErrorIs(t, err, ErrOperationNotPermitted)
+	assert.Zero(t, n)
+	assert.Equal(t, "lorem_ipsum", bw.String())
+}
@@ -1,19 +1,38 @@
 package gonvoy
 
 import (
+	"container/list"
+	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ardikabs/gonvoy/pkg/util"
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
 )
 
 // Cache is an interface that defines methods for storing and retrieving data in an internal cache.
 // It is designed to maintain data persistently throughout Envoy's lifespan.
+//
+// The cache might be bounded by a capacity, in which the least recently used entry is evicted once the capacity is exceeded,
+// and entries might expire after their time-to-live (TTL), see CacheCapacity and CacheDefaultTTL options.
+//
+// Every lookup, including Load, takes the exclusive cache lock to promote the entry on the least recently used order,
+// hence the concurrent lookups are serialized, even across the Envoy worker threads.
+// The lock is only held for the map lookup and the promotion, never while calling a loader or assigning the receiver.
 type Cache interface {
 	// Store allows you to save a value of any type under a key of any type.
+	// The entry expires after the default TTL, if any.
 	//
 	// Please use caution! The Store function overwrites any existing data.
 	Store(key, value any)
 
+	// StoreWithTTL is similar to Store, but the entry expires after the given TTL.
+	// A non-positive TTL means the entry never expires.
+	//
+	// Please use caution! The StoreWithTTL function overwrites any existing data.
+	StoreWithTTL(key, value any, ttl time.Duration)
+
 	// Load retrieves a value associated with a specific key and assigns it to the receiver.
 	//
 	// It returns true if a compatible value is successfully loaded,
@@ -31,18 +50,226 @@ type Cache interface {
 	//   receiver := new(mystruct)
 	//   _, _ = cache.Load("keyName", &receiver)
 	Load(key, receiver any) (ok bool, err error)
+
+	// LoadOrStore retrieves the existing value for a key and assigns it to the receiver, if present.
+	// Otherwise, it stores the given value with the default TTL, and assigns the given value to the receiver.
+	// The receiver can be nil, in which case only the store operation is relevant.
+	//
+	// It returns true if the value was loaded, false if the value was stored.
+	//
+	// If the receiver is not a pointer to the stored data type,
+	// LoadOrStore will return an ErrIncompatibleReceiver.
+	LoadOrStore(key, value, receiver any) (loaded bool, err error)
+
+	// GetOrLoad returns the value associated with a specific key, otherwise it calls the loader and stores the loaded value.
+	// It is intended for expensive initialization, such as fetching a JWKS or a tenant configuration.
+	//
+	// Concurrent callers of the same key share a single loader call, the others wait for its result.
+	// By default, the loaded value expires after the default TTL, and a failed load is not cached.
+	// See CacheLoadWithTTL, CacheLoadWithNegativeTTL, and CacheLoadWithRefreshAhead options.
+	//
+	// Example usage:
+	//   v, err := cache.GetOrLoad("jwks", func() (any, error) {
+	//     return fetchJWKS(url)
+	//   }, gonvoy.CacheLoadWithTTL(time.Hour), gonvoy.CacheLoadWithRefreshAhead(5*time.Minute))
+	GetOrLoad(key any, loader CacheLoaderFunc, opts ...CacheLoadOption) (any, error)
+
+	// Delete removes the value associated with a specific key.
+	Delete(key any)
+
+	// Range calls f sequentially for each unexpired key and value present in the cache.
+	// If f returns false, Range stops the iteration.
+	//
+	// Range operates on a snapshot of the cache, hence f may call any of the Cache methods.
+	Range(f func(key, value any) bool)
+}
+
+const (
+	cacheLookupsMetricName   = "cache_lookups_total"
+	cacheEvictionsMetricName = "cache_evictions_total"
+)
+
+// cacheEvictionReason is the reason of a cache eviction, reported as the `reason` label of the evictions counter.
+type cacheEvictionReason int
+
+const (
+	cacheEvictionCapacity cacheEvictionReason = iota
+	cacheEvictionExpired
+)
+
+var cacheEvictionReasons = [...]string{
+	cacheEvictionCapacity: "capacity",
+	cacheEvictionExpired:  "expired",
+}
+
+// cacheLookupResult is the result of a cache lookup, reported as the `result` label of the lookups counter.
+// A lookup that finds a failed load cached by Cache.GetOrLoad, see CacheLoadWithNegativeTTL, is a negative hit,
+// so that it doesn't inflate the hit ratio while the loader is failing.
+type cacheLookupResult int
+
+const (
+	cacheLookupHit cacheLookupResult = iota
+	cacheLookupMiss
+	cacheLookupNegativeHit
+)
+
+var cacheLookupResults = [...]string{
+	cacheLookupHit:         "hit",
+	cacheLookupMiss:        "miss",
+	cacheLookupNegativeHit: "negative_hit",
+}
+
+// lookupResultOf returns the lookup result of the entry found in the cache, if any.
+func lookupResultOf(entry *cacheEntry) cacheLookupResult {
+	switch {
+	case entry == nil:
+		return cacheLookupMiss
+	case entry.err != nil:
+		return cacheLookupNegativeHit
+	default:
+		return cacheLookupHit
+	}
+}
+
+// cacheCounters are the counters reporting the cache lookups and evictions.
+// They are resolved once the metrics are bound, hence the cache lookups never define a metric.
+type cacheCounters struct {
+	lookups   [len(cacheLookupResults)]api.CounterMetric
+	evictions [len(cacheEvictionReasons)]api.CounterMetric
+}
+
+func newCacheCounters(metrics Metrics) *cacheCounters {
+	counters := &cacheCounters{}
+	for result, name := range cacheLookupResults {
+		counters.lookups[result] = metrics.Counter(cacheLookupsMetricName, "result", name)
+	}
+
+	for reason, name := range cacheEvictionReasons {
+		counters.evictions[reason] = metrics.Counter(cacheEvictionsMetricName, "reason", name)
+	}
+
+	return counters
+}
+
+// CacheLoaderFunc is a function that loads a value for Cache.GetOrLoad.
+type CacheLoaderFunc func() (any, error)
+
+// CacheLoadOptions represents the options for Cache.GetOrLoad.
+type CacheLoadOptions struct {
+	ttl          time.Duration
+	negativeTTL  time.Duration
+	refreshAhead time.Duration
+}
+
+type CacheLoadOption func(o *CacheLoadOptions)
+
+// NewCacheLoadOptions creates the options for Cache.GetOrLoad, where the loaded value expires after the given default TTL.
+func NewCacheLoadOptions(defaultTTL time.Duration, opts ...CacheLoadOption) *CacheLoadOptions {
+	o := &CacheLoadOptions{
+		ttl: defaultTTL,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// CacheLoadWithTTL sets the time-to-live of the loaded value, instead of the default TTL.
+// A non-positive TTL means the loaded value never expires.
+func CacheLoadWithTTL(ttl time.Duration) CacheLoadOption {
+	return func(o *CacheLoadOptions) {
+		o.ttl = ttl
+	}
+}
+
+// CacheLoadWithNegativeTTL caches the loader error for the given TTL,
+// hence the subsequent calls return the same error without calling the loader until it expires.
+// It prevents a failing upstream from being hammered by every request.
+func CacheLoadWithNegativeTTL(ttl time.Duration) CacheLoadOption {
+	return func(o *CacheLoadOptions) {
+		o.negativeTTL = ttl
+	}
+}
+
+// CacheLoadWithRefreshAhead refreshes the value in the background once it is about to expire within the given window.
+// Meanwhile, callers keep getting the current value, hence the request paths never block on a refresh.
+// If the refresh fails, the current value is kept until it expires.
+// This setting applies only when the loaded value has a TTL.
+func CacheLoadWithRefreshAhead(window time.Duration) CacheLoadOption {
+	return func(o *CacheLoadOptions) {
+		o.refreshAhead = window
+	}
+}
+
+type cacheEntry struct {
+	key       any
+	value     any
+	err       error
+	expiresAt time.Time
+}
+
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// cacheCall represents an in-flight loader call of Cache.GetOrLoad.
+type cacheCall struct {
+	wg    sync.WaitGroup
+	value any
+	err   error
 }
 
 type inmemoryCache struct {
-	stash sync.Map
+	mu    sync.Mutex
+	items map[any]*list.Element
+	lru   *list.List
+	calls map[any]*cacheCall
+
+	capacity   int
+	defaultTTL time.Duration
+	counters   atomic.Pointer[cacheCounters]
+	now        func() time.Time
 }
 
-func newInternalCache() *inmemoryCache {
-	return &inmemoryCache{}
+// newInternalCache creates an in-memory cache, bounded by the capacity, and entries expire after the defaultTTL.
+// A non-positive capacity means the cache is unbounded, and a non-positive defaultTTL means entries never expire by default.
+// The metrics is used to report the cache lookups and evictions, see bindMetrics, where a nil metrics discards them.
+func newInternalCache(capacity int, defaultTTL time.Duration, metrics Metrics) *inmemoryCache {
+	c := &inmemoryCache{
+		items:      make(map[any]*list.Element),
+		lru:        list.New(),
+		calls:      make(map[any]*cacheCall),
+		capacity:   capacity,
+		defaultTTL: defaultTTL,
+		now:        time.Now,
+	}
+
+	c.bindMetrics(metrics)
+	return c
+}
+
+// bindMetrics resolves the counters reporting the cache lookups and evictions from the metrics, where a nil metrics discards them.
+// It is safe to call while the cache is in use, e.g., once the root filter configuration is reloaded.
+func (c *inmemoryCache) bindMetrics(metrics Metrics) {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+
+	c.counters.Store(newCacheCounters(metrics))
 }
 
 func (c *inmemoryCache) Store(key, value any) {
-	c.stash.Store(key, value)
+	c.StoreWithTTL(key, value, c.defaultTTL)
+}
+
+func (c *inmemoryCache) StoreWithTTL(key, value any, ttl time.Duration) {
+	c.mu.Lock()
+	evicted := c.store(key, value, ttl)
+	c.mu.Unlock()
+
+	c.recordEviction(cacheEvictionCapacity, evicted)
 }
 
 func (c *inmemoryCache) Load(key, receiver any) (bool, error) {
@@ -50,7 +277,7 @@ func (c *inmemoryCache) Load(key, receiver any) (bool, error) {
 		return false, ErrNilReceiver
 	}
 
-	v, ok := c.stash.Load(key)
+	v, ok := c.loadValue(key)
 	if !ok {
 		return false, nil
 	}
@@ -61,3 +288,326 @@ func (c *inmemoryCache) Load(key, receiver any) (bool, error) {
 
 	return true, nil
 }
+
+func (c *inmemoryCache) LoadOrStore(key, value, receiver any) (bool, error) {
+	actual, loaded := c.loadOrStoreValue(key, value)
+	if receiver == nil {
+		return loaded, nil
+	}
+
+	if !util.CastTo(receiver, actual) {
+		return loaded, ErrIncompatibleReceiver
+	}
+
+	return loaded, nil
+}
+
+func (c *inmemoryCache) GetOrLoad(key any, loader CacheLoaderFunc, opts ...CacheLoadOption) (any, error) {
+	o := NewCacheLoadOptions(c.defaultTTL, opts...)
+
+	c.mu.Lock()
+	entry, expired := c.lookup(key)
+	if entry != nil {
+		refresh := o.refreshAhead > 0 && entry.err == nil && !entry.expiresAt.IsZero() &&
+			entry.expiresAt.Sub(c.now()) <= o.refreshAhead
+
+		if refresh {
+			if call, started := c.startLoad(key); started {
+				go c.load(key, call, loader, o, true)
+			}
+		}
+		c.mu.Unlock()
+
+		c.recordLookup(lookupResultOf(entry))
+		return entry.value, entry.err
+	}
+
+	call, started := c.startLoad(key)
+	c.mu.Unlock()
+
+	c.recordLookup(cacheLookupMiss)
+	c.recordEviction(cacheEvictionExpired, expired)
+
+	if started {
+		c.load(key, call, loader, o, false)
+	} else {
+		call.wg.Wait()
+	}
+
+	return call.value, call.err
+}
+
+func (c *inmemoryCache) Delete(key any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.remove(elem)
+	}
+}
+
+func (c *inmemoryCache) Range(f func(key, value any) bool) {
+	now := c.now()
+
+	c.mu.Lock()
+	entries := make([]cacheEntry, 0, c.lru.Len())
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*cacheEntry)
+		if entry.err == nil && !entry.expired(now) {
+			entries = append(entries, *entry)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, entry := range entries {
+		if !f(entry.key, entry.value) {
+			return
+		}
+	}
+}
+
+// loadValue returns the raw value associated with the key, and reports the lookup result.
+func (c *inmemoryCache) loadValue(key any) (any, bool) {
+	c.mu.Lock()
+	entry, expired := c.lookup(key)
+	c.mu.Unlock()
+
+	ok := entry != nil && entry.err == nil
+	c.recordLookup(lookupResultOf(entry))
+	c.recordEviction(cacheEvictionExpired, expired)
+	if !ok {
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+// loadOrStoreValue returns the existing raw value associated with the key, if present.
+// Otherwise, it stores and returns the given value.
+func (c *inmemoryCache) loadOrStoreValue(key, value any) (actual any, loaded bool) {
+	c.mu.Lock()
+	entry, expired := c.lookup(key)
+	loaded = entry != nil && entry.err == nil
+	evicted := 0
+	if loaded {
+		actual = entry.value
+	} else {
+		actual = value
+		evicted = c.store(key, value, c.defaultTTL)
+	}
+	c.mu.Unlock()
+
+	c.recordLookup(lookupResultOf(entry))
+	c.recordEviction(cacheEvictionExpired, expired)
+	c.recordEviction(cacheEvictionCapacity, evicted)
+	return actual, loaded
+}
+
+// lookup returns a copy of the entry associated with the key, and marks it as the most recently used.
+// An expired entry is removed, and reported through the expired return value.
+// It must be called with the lock held.
+func (c *inmemoryCache) lookup(key any) (entry *cacheEntry, expired int) {
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, 0
+	}
+
+	e := elem.Value.(*cacheEntry)
+	if e.expired(c.now()) {
+		c.remove(elem)
+		return nil, 1
+	}
+
+	c.lru.MoveToFront(elem)
+	copied := *e
+	return &copied, 0
+}
+
+// startLoad returns the in-flight loader call of the key, otherwise it registers a new one, and reports it through started.
+// It must be called with the lock held.
+func (c *inmemoryCache) startLoad(key any) (call *cacheCall, started bool) {
+	if call, ok := c.calls[key]; ok {
+		return call, false
+	}
+
+	call = new(cacheCall)
+	call.wg.Add(1)
+	c.calls[key] = call
+	return call, true
+}
+
+// load calls the loader, stores its result, and releases the callers waiting for it.
+// A panic in the loader is recovered and returned as an error, as the background refresh would otherwise crash Envoy.
+// During the background refresh, a failed load keeps the current value.
+func (c *inmemoryCache) load(key any, call *cacheCall, loader CacheLoaderFunc, o *CacheLoadOptions, background bool) {
+	defer call.wg.Done()
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				call.err = fmt.Errorf("cache loader panicked; %v, %w", r, ErrRuntime)
+			}
+		}()
+
+		call.value, call.err = loader()
+	}()
+
+	c.mu.Lock()
+	delete(c.calls, key)
+
+	evicted := 0
+	switch {
+	case call.err == nil:
+		evicted = c.store(key, call.value, o.ttl)
+	case !background && o.negativeTTL > 0:
+		evicted = c.storeEntry(&cacheEntry{key: key, err: call.err}, o.negativeTTL)
+	}
+	c.mu.Unlock()
+
+	c.recordEviction(cacheEvictionCapacity, evicted)
+}
+
+// store saves the value under the key, and evicts the least recently used entries once the capacity is exceeded.
+// It returns the number of evicted entries, and must be called with the lock held.
+func (c *inmemoryCache) store(key, value any, ttl time.Duration) (evicted int) {
+	return c.storeEntry(&cacheEntry{key: key, value: value}, ttl)
+}
+
+// storeEntry must be called with the lock held.
+func (c *inmemoryCache) storeEntry(entry *cacheEntry, ttl time.Duration) (evicted int) {
+	if ttl > 0 {
+		entry.expiresAt = c.now().Add(ttl)
+	}
+
+	if elem, ok := c.items[entry.key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return 0
+	}
+
+	c.items[entry.key] = c.lru.PushFront(entry)
+
+	for c.capacity > 0 && c.lru.Len() > c.capacity {
+		c.remove(c.lru.Back())
+		evicted++
+	}
+
+	return evicted
+}
+
+// remove must be called with the lock held.
+func (c *inmemoryCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.items, entry.key)
+}
+
+func (c *inmemoryCache) recordLookup(result cacheLookupResult) {
+	c.counters.Load().lookups[result].Increment(1)
+}
+
+func (c *inmemoryCache) recordEviction(reason cacheEvictionReason, count int) {
+	if count == 0 {
+		return
+	}
+
+	c.counters.Load().evictions[reason].Increment(int64(count))
+}
+
+// TypedCache is a type-safe wrapper of Cache, which removes the need of a receiver on every load.
+// It shares the same storage with the underlying Cache, hence the keys must be unique across the cache users.
+//
+// Example usage:
+//
+//	jwks := gonvoy.NewTypedCache[string, *JWKS](c.GetCache())
+//	if keys, ok := jwks.Load(issuer); ok {
+//		...
+//	}
+type TypedCache[K comparable, V any] struct {
+	cache Cache
+}
+
+// NewTypedCache creates a TypedCache on top of the given Cache.
+func NewTypedCache[K comparable, V any](cache Cache) *TypedCache[K, V] {
+	return &TypedCache[K, V]{cache: cache}
+}
+
+// Store saves the value under the key, it expires after the default TTL, if any.
+func (c *TypedCache[K, V]) Store(key K, value V) {
+	c.cache.Store(key, value)
+}
+
+// StoreWithTTL saves the value under the key, it expires after the given TTL.
+func (c *TypedCache[K, V]) StoreWithTTL(key K, value V, ttl time.Duration) {
+	c.cache.StoreWithTTL(key, value, ttl)
+}
+
+// Load returns the value associated with the key.
+// It returns false if no value is found, or the stored value is not a V.
+func (c *TypedCache[K, V]) Load(key K) (value V, ok bool) {
+	if ic, isInternal := c.cache.(*inmemoryCache); isInternal {
+		v, found := ic.loadValue(key)
+		if !found {
+			return value, false
+		}
+
+		value, ok = v.(V)
+		return value, ok
+	}
+
+	ok, err := c.cache.Load(key, &value)
+	return value, ok && err == nil
+}
+
+// LoadOrStore returns the existing value associated with the key, if present.
+// Otherwise, it stores and returns the given value.
+// The loaded result is true if the value was loaded, false if stored.
+func (c *TypedCache[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	if ic, isInternal := c.cache.(*inmemoryCache); isInternal {
+		v, loaded := ic.loadOrStoreValue(key, value)
+		actual, _ = v.(V)
+		return actual, loaded
+	}
+
+	loaded, _ = c.cache.LoadOrStore(key, value, &actual)
+	return actual, loaded
+}
+
+// GetOrLoad returns the value associated with the key, otherwise it calls the loader and stores the loaded value, see Cache.GetOrLoad.
+func (c *TypedCache[K, V]) GetOrLoad(key K, loader func() (V, error), opts ...CacheLoadOption) (value V, err error) {
+	v, err := c.cache.GetOrLoad(key, func() (any, error) {
+		return loader()
+	}, opts...)
+	if err != nil {
+		return value, err
+	}
+
+	value, ok := v.(V)
+	if !ok {
+		return value, ErrIncompatibleReceiver
+	}
+
+	return value, nil
+}
+
+// Delete removes the value associated with the key.
+func (c *TypedCache[K, V]) Delete(key K) {
+	c.cache.Delete(key)
+}
+
+// Range calls f sequentially for each key and value of the expected types present in the cache.
+// If f returns false, Range stops the iteration.
+func (c *TypedCache[K, V]) Range(f func(key K, value V) bool) {
+	c.cache.Range(func(k, v any) bool {
+		key, ok := k.(K)
+		if !ok {
+			return true
+		}
+
+		value, ok := v.(V)
+		if !ok {
+			return true
+		}
+
+		return f(key, value)
+	})
+}
@@ -2,14 +2,20 @@ package gonvoy
 
 import (
 	"bytes"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestCache_StoreAndLoad(t *testing.T) {
-	lc := newInternalCache()
+	lc := newInternalCache(0, 0, nil)
 
 	t.Run("pointer object", func(t *testing.T) {
 		source := bytes.NewReader([]byte("testing"))
@@ -58,3 +64,295 @@ func TestCache_StoreAndLoad(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestCache_Expiry(t *testing.T) {
+	now := time.Now()
+	lc := newInternalCache(0, time.Minute, nil)
+	lc.now = func() time.Time { return now }
+
+	lc.Store("default", "value")
+	lc.StoreWithTTL("short", "value", time.Second)
+	lc.StoreWithTTL("forever", "value", 0)
+
+	now = now.Add(2 * time.Second)
+
+	var v string
+	ok, err := lc.Load("short", &v)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = lc.Load("default", &v)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	now = now.Add(time.Hour)
+
+	var keys []any
+	lc.Range(func(key, value any) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, []any{"forever"}, keys)
+}
+
+func TestCache_Eviction(t *testing.T) {
+	lc := newInternalCache(2, 0, nil)
+	lc.Store("a", 1)
+	lc.Store("b", 2)
+
+	var v int
+	ok, _ := lc.Load("a", &v)
+	assert.True(t, ok)
+
+	// "b" is the least recently used entry
+	lc.Store("c", 3)
+
+	ok, _ = lc.Load("b", &v)
+	assert.False(t, ok)
+	ok, _ = lc.Load("a", &v)
+	assert.True(t, ok)
+	ok, _ = lc.Load("c", &v)
+	assert.True(t, ok)
+
+	lc.Delete("a")
+	ok, _ = lc.Load("a", &v)
+	assert.False(t, ok)
+}
+
+func TestCache_LoadOrStore(t *testing.T) {
+	lc := newInternalCache(0, 0, nil)
+
+	var v string
+	loaded, err := lc.LoadOrStore("foo", "first", &v)
+	require.NoError(t, err)
+	assert.False(t, loaded)
+	assert.Equal(t, "first", v)
+
+	loaded, err = lc.LoadOrStore("foo", "second", &v)
+	require.NoError(t, err)
+	assert.True(t, loaded)
+	assert.Equal(t, "first", v)
+
+	var i int
+	_, err = lc.LoadOrStore("foo", "third", &i)
+	assert.ErrorIs(t, err, ErrIncompatibleReceiver)
+
+	loaded, err = lc.LoadOrStore("bar", "value", nil)
+	require.NoError(t, err)
+	assert.False(t, loaded)
+}
+
+func TestCache_Metrics(t *testing.T) {
+	hit := mock_envoy.NewCounterMetric(t)
+	hit.EXPECT().Increment(int64(1)).Twice()
+	miss := mock_envoy.NewCounterMetric(t)
+	miss.EXPECT().Increment(int64(1)).Times(3)
+	negativeHit := mock_envoy.NewCounterMetric(t)
+	negativeHit.EXPECT().Increment(int64(1)).Twice()
+	evicted := mock_envoy.NewCounterMetric(t)
+	evicted.EXPECT().Increment(int64(1)).Twice()
+
+	cc := mock_envoy.NewConfigCallbackHandler(t)
+	cc.EXPECT().DefineCounterMetric("cache_lookups_total_result=hit").Return(hit).Once()
+	cc.EXPECT().DefineCounterMetric("cache_lookups_total_result=miss").Return(miss).Once()
+	cc.EXPECT().DefineCounterMetric("cache_lookups_total_result=negative_hit").Return(negativeHit).Once()
+	cc.EXPECT().DefineCounterMetric("cache_evictions_total_reason=capacity").Return(evicted).Once()
+	cc.EXPECT().DefineCounterMetric("cache_evictions_total_reason=expired").Return(mock_envoy.NewCounterMetric(t)).Once()
+
+	cfg := newInternalConfig(ConfigOptions{CacheCapacity: 1})
+	cfg.setCallbacks(cc)
+
+	var v string
+	cfg.internalCache.Store("foo", "value")
+	_, _ = cfg.internalCache.Load("foo", &v)
+	_, _ = cfg.internalCache.Load("bar", &v)
+	_, _ = cfg.internalCache.LoadOrStore("bar", "value", &v)
+	_, _ = cfg.internalCache.Load("bar", &v)
+
+	// A failed load cached by the negative TTL is not counted as a hit.
+	loader := func() (any, error) { return nil, errors.New("unavailable") }
+	_, _ = cfg.internalCache.GetOrLoad("baz", loader, CacheLoadWithNegativeTTL(time.Minute))
+	_, _ = cfg.internalCache.GetOrLoad("baz", loader, CacheLoadWithNegativeTTL(time.Minute))
+	_, _ = cfg.internalCache.Load("baz", &v)
+}
+
+func TestCache_MetricsWithoutCallbacks(t *testing.T) {
+	cfg := newInternalConfig(ConfigOptions{CacheCapacity: 1})
+
+	assert.NotPanics(t, func() {
+		var v string
+		cfg.internalCache.Store("foo", "value")
+		cfg.internalCache.Store("bar", "value")
+		_, _ = cfg.internalCache.Load("foo", &v)
+		_, _ = cfg.internalCache.Load("bar", &v)
+	})
+
+	cc := mock_envoy.NewConfigCallbackHandler(t)
+	cc.EXPECT().DefineCounterMetric(mock.Anything).Return(mock_envoy.NewCounterMetric(t)).Times(5)
+	cfg.setCallbacks(cc)
+	cfg.setCallbacks(cc)
+
+	cfg.setCallbacks(nil)
+	assert.Equal(t, noopMetric{}, cfg.internalCache.(*inmemoryCache).counters.Load().lookups[cacheLookupHit])
+}
+
+func TestTypedCache(t *testing.T) {
+	lc := newInternalCache(0, 0, nil)
+	tc := NewTypedCache[string, *bytes.Reader](lc)
+
+	source := bytes.NewReader([]byte("testing"))
+	tc.Store("foo", source)
+
+	v, ok := tc.Load("foo")
+	assert.True(t, ok)
+	assert.Same(t, source, v)
+
+	_, ok = tc.Load("bar")
+	assert.False(t, ok)
+
+	actual, loaded := tc.LoadOrStore("foo", bytes.NewReader(nil))
+	assert.True(t, loaded)
+	assert.Same(t, source, actual)
+
+	// values of other types are invisible to the typed cache
+	lc.Store("baz", "string")
+	_, ok = tc.Load("baz")
+	assert.False(t, ok)
+
+	var keys []string
+	tc.Range(func(key string, value *bytes.Reader) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, []string{"foo"}, keys)
+
+	tc.Delete("foo")
+	_, ok = tc.Load("foo")
+	assert.False(t, ok)
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	t.Run("concurrent callers share a single load", func(t *testing.T) {
+		lc := newInternalCache(0, 0, nil)
+
+		var calls atomic.Int32
+		release := make(chan struct{})
+		loader := func() (any, error) {
+			calls.Add(1)
+			<-release
+			return "value", nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := lc.GetOrLoad("foo", loader)
+				assert.NoError(t, err)
+				assert.Equal(t, "value", v)
+			}()
+		}
+
+		// give the goroutines a chance to join the in-flight load
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+
+		var v string
+		ok, err := lc.Load("foo", &v)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("failed load is not cached by default", func(t *testing.T) {
+		lc := newInternalCache(0, 0, nil)
+		loaderErr := errors.New("upstream unavailable")
+
+		_, err := lc.GetOrLoad("foo", func() (any, error) { return nil, loaderErr })
+		assert.ErrorIs(t, err, loaderErr)
+
+		v, err := lc.GetOrLoad("foo", func() (any, error) { return "value", nil })
+		assert.NoError(t, err)
+		assert.Equal(t, "value", v)
+	})
+
+	t.Run("failed load is cached with negative TTL", func(t *testing.T) {
+		now := time.Now()
+		lc := newInternalCache(0, 0, nil)
+		lc.now = func() time.Time { return now }
+		loaderErr := errors.New("upstream unavailable")
+
+		_, err := lc.GetOrLoad("foo", func() (any, error) { return nil, loaderErr }, CacheLoadWithNegativeTTL(time.Second))
+		assert.ErrorIs(t, err, loaderErr)
+
+		_, err = lc.GetOrLoad("foo", func() (any, error) { return "value", nil })
+		assert.ErrorIs(t, err, loaderErr)
+
+		// negative entries are invisible to the other operations
+		var s string
+		ok, err := lc.Load("foo", &s)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		now = now.Add(2 * time.Second)
+		v, err := lc.GetOrLoad("foo", func() (any, error) { return "value", nil })
+		assert.NoError(t, err)
+		assert.Equal(t, "value", v)
+	})
+
+	t.Run("panic in the loader is returned as an error", func(t *testing.T) {
+		lc := newInternalCache(0, 0, nil)
+
+		_, err := lc.GetOrLoad("foo", func() (any, error) { panic("boom") })
+		assert.ErrorIs(t, err, ErrRuntime)
+	})
+
+	t.Run("refresh ahead serves the current value while refreshing in the background", func(t *testing.T) {
+		var mu sync.Mutex
+		now := time.Now()
+		lc := newInternalCache(0, 0, nil)
+		lc.now = func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return now
+		}
+
+		opts := []CacheLoadOption{CacheLoadWithTTL(time.Minute), CacheLoadWithRefreshAhead(10 * time.Second)}
+		v, err := lc.GetOrLoad("foo", func() (any, error) { return "first", nil }, opts...)
+		require.NoError(t, err)
+		assert.Equal(t, "first", v)
+
+		mu.Lock()
+		now = now.Add(55 * time.Second)
+		mu.Unlock()
+
+		refreshed := make(chan struct{})
+		v, err = lc.GetOrLoad("foo", func() (any, error) {
+			defer close(refreshed)
+			return "second", nil
+		}, opts...)
+		require.NoError(t, err)
+		assert.Equal(t, "first", v)
+
+		<-refreshed
+		assert.Eventually(t, func() bool {
+			v, err := lc.GetOrLoad("foo", func() (any, error) { return "third", nil })
+			return err == nil && v == "second"
+		}, time.Second, time.Millisecond)
+	})
+}
+
+func TestTypedCache_GetOrLoad(t *testing.T) {
+	tc := NewTypedCache[string, int](newInternalCache(0, 0, nil))
+
+	v, err := tc.GetOrLoad("foo", func() (int, error) { return 42, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 42, v)
+
+	v, err = tc.GetOrLoad("foo", func() (int, error) { return 0, errors.New("must not be called") })
+	require.NoError(t, err)
+	assert.Equal(t, 42, v)
+}
@@ -2,10 +2,12 @@ package gonvoy
 
 import (
 	"errors"
+	"slices"
 	"strings"
 
 	"github.com/ardikabs/gonvoy/pkg/util"
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/go-logr/logr"
 )
 
 var (
@@ -13,11 +15,19 @@ var (
 )
 
 type internalConfig struct {
+	filterName    string
 	filterConfig  interface{}
 	callbacks     api.ConfigCallbacks
 	internalCache Cache
 	metricsPrefix string
 
+	// filterConfigKeys records the keys given on the filter config, so that the merge tells apart an unset child field, see fieldPresence.
+	filterConfigKeys fieldPresence
+
+	// metrics is the metric registry shared across requests, as well as across the child configs.
+	metrics          *metrics
+	histogramBuckets []uint64
+
 	strictBodyAccess                bool
 	allowRequestBodyRead            bool
 	allowRequestBodyWrite           bool
@@ -25,15 +35,39 @@ type internalConfig struct {
 	allowResponseBodyWrite          bool
 	preserveContentLengthOnRequest  bool
 	preserveContentLengthOnResponse bool
+	requestBodyStreaming            bool
+	responseBodyStreaming           bool
 
 	autoReloadRoute bool
+	errorRenderer   ErrorRenderer
+	negotiation     *contentNegotiation
+
+	// scope ties the filter config lifecycle hooks to the filter config, see configScope.
+	scope  *configScope
+	logger logr.Logger
+
+	// configErr holds the validation or initialization result of the merged filter config,
+	// since a merge can't be rejected as it happens lazily on the request path.
+	configErr error
+
+	// version is renewed once the root filter config is renewed, since the root config is renewed in place,
+	// which tells apart the parent configs of a merge, see configMerges.
+	version uint64
+
+	// merges holds the merged configs of the child config, see configParser.Merge.
+	merges *configMerges
 }
 
 func newInternalConfig(options ConfigOptions) *internalConfig {
 	gc := &internalConfig{
-		internalCache:   newInternalCache(),
+		filterName:      options.FilterName,
 		autoReloadRoute: options.AutoReloadRoute,
+		errorRenderer:   options.ErrorRenderer,
+		negotiation:     newContentNegotiation(options.Renderers, options.NegotiationFallback),
 		metricsPrefix:   options.MetricsPrefix,
+		logger:          newConfigLogger(options.ConfigLogger),
+
+		histogramBuckets: newHistogramBuckets(options.HistogramBuckets),
 
 		strictBodyAccess:                !options.DisableStrictBodyAccess,
 		allowRequestBodyRead:            options.EnableRequestBodyRead,
@@ -42,24 +76,87 @@ func newInternalConfig(options ConfigOptions) *internalConfig {
 		allowResponseBodyWrite:          options.EnableResponseBodyWrite,
 		preserveContentLengthOnRequest:  options.DisableChunkedEncodingRequest,
 		preserveContentLengthOnResponse: options.DisableChunkedEncodingResponse,
+		requestBodyStreaming:            options.EnableRequestBodyStreaming,
+		responseBodyStreaming:           options.EnableResponseBodyStreaming,
 	}
 
+	gc.metrics = newMetrics(gc.defineCounterMetric, gc.defineGaugeMetric, gc.defineHistogramMetric)
+	// The cache metrics are discarded until the config callbacks are set, see setCallbacks.
+	gc.internalCache = newInternalCache(options.CacheCapacity, options.CacheDefaultTTL, nil)
 	return gc
+}
+
+// setCallbacks sets the config callbacks, which the metrics are defined against.
+// The metric handles are bound to the config callbacks, hence once they are renewed, the metric registry is reset,
+// and the cache counters are resolved again against the renewed config callbacks.
+// The config callbacks are replaced while holding the registry lock, since the requests define the metrics concurrently.
+func (c *internalConfig) setCallbacks(callbacks api.ConfigCallbacks) {
+	if c.callbacks == callbacks {
+		return
+	}
+
+	c.metrics.renew(func() {
+		c.callbacks = callbacks
+	})
+
+	cache, ok := c.internalCache.(*inmemoryCache)
+	if !ok {
+		return
+	}
+
+	if callbacks == nil {
+		cache.bindMetrics(noopMetrics{})
+		return
+	}
 
+	cache.bindMetrics(c.metrics)
 }
 
+// metricRegistry returns the metric registry of the config, see newInternalConfig,
+// or a registry that discards every metric for the configs built without it.
+func (c *internalConfig) metricRegistry() Metrics {
+	if c.metrics == nil {
+		return noopMetrics{}
+	}
+
+	return c.metrics
+}
+
+// defineCounterMetric defines the counter against the config callbacks.
+// Like the other define functions, it is called by the metric registry while holding its lock, see metrics.renew.
 func (c *internalConfig) defineCounterMetric(name string) api.CounterMetric {
+	if c.callbacks == nil {
+		return noopMetric{}
+	}
+
 	name = strings.ToLower(util.ReplaceAllEmptySpace(c.metricsPrefix + name))
 	return c.callbacks.DefineCounterMetric(name)
 }
 
 func (c *internalConfig) defineGaugeMetric(name string) api.GaugeMetric {
+	if c.callbacks == nil {
+		return noopMetric{}
+	}
+
 	name = strings.ToLower(util.ReplaceAllEmptySpace(c.metricsPrefix + name))
 	return c.callbacks.DefineGaugeMetric(name)
 }
 
-func (c *internalConfig) defineHistogramMetric(name string) api.HistogramMetric {
-	panic("NOT IMPLEMENTED")
+func (c *internalConfig) defineHistogramMetric(name string, labelKeyValues ...string) HistogramMetric {
+	return newHistogram(c.defineCounterMetric, c.histogramBuckets, name, labelKeyValues...)
+}
+
+// newHistogramBuckets returns the sorted and deduplicated copy of the histogram buckets, or a copy of the DefaultHistogramBuckets if none is given.
+func newHistogramBuckets(buckets []uint64) []uint64 {
+	if len(buckets) == 0 {
+		return slices.Clone(DefaultHistogramBuckets)
+	}
+
+	sorted := make([]uint64, len(buckets))
+	copy(sorted, buckets)
+	slices.Sort(sorted)
+
+	return slices.Compact(sorted)
 }
 
 func validateFilterConfig(filterConfig interface{}) error {
@@ -79,15 +176,20 @@ func applyInternalConfig(c *context, cfg *internalConfig) error {
 		return errInternalConfigNotFound
 	}
 
-	if err := validateFilterConfig(cfg.filterConfig); err != nil {
-		return err
+	// The filter config has been validated at the config-load time, see configParser.
+	if cfg.configErr != nil {
+		return cfg.configErr
 	}
 
+	c.filterName = cfg.filterName
 	c.filterConfig = cfg.filterConfig
+	c.configScope = cfg.scope
 	c.cache = cfg.internalCache
-	c.metrics = newMetrics(cfg.defineCounterMetric, cfg.defineGaugeMetric, cfg.defineHistogramMetric)
+	c.metrics = cfg.metricRegistry()
 
 	c.autoReloadRoute = cfg.autoReloadRoute
+	c.errorRenderer = cfg.errorRenderer
+	c.contentNegotiation = cfg.negotiation
 
 	c.strictBodyAccess = cfg.strictBodyAccess
 	c.requestBodyAccessRead = cfg.allowRequestBodyRead
@@ -96,5 +198,7 @@ func applyInternalConfig(c *context, cfg *internalConfig) error {
 	c.responseBodyAccessWrite = cfg.allowResponseBodyWrite
 	c.preserveContentLengthOnRequest = cfg.preserveContentLengthOnRequest
 	c.preserveContentLengthOnResponse = cfg.preserveContentLengthOnResponse
+	c.requestBodyStreaming = cfg.requestBodyStreaming
+	c.responseBodyStreaming = cfg.responseBodyStreaming
 	return nil
 }
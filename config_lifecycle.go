@@ -0,0 +1,121 @@
+package gonvoy
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// ConfigInitializer is an optional interface that can be implemented by the filter configuration, see ConfigOptions.FilterConfig.
+// It is intended to build expensive objects once per filter configuration version, instead of once per request,
+// such as compiled regular expressions, HTTP clients, or key materials.
+// The built objects can be kept within the filter configuration, and retrieved by every request through GetFilterConfig.
+//
+// OnConfigInit is called once the filter configuration has been parsed and validated.
+// For the route-level (child) filter configuration, it is called exactly once per parent filter configuration it is merged with,
+// unless AlwaysUseChildConfig is enabled, in which it is called once parsed.
+// If an error is returned, the filter configuration is rejected.
+//
+// The hook receives a ConfigContext rather than a RuntimeContext, since there is no request at the config-load time,
+// hence the request-scoped accessors of RuntimeContext, such as StreamInfo or DynamicMetadata, are unavailable.
+// ConfigContext is the config-scoped subset of RuntimeContext, so that the filter configuration is given through GetFilterConfig,
+// along with the same Cache and Metrics handed to every request of the filter configuration.
+type ConfigInitializer interface {
+	OnConfigInit(c ConfigContext) error
+}
+
+// ConfigDestroyer is an optional interface that can be implemented by the filter configuration, see ConfigOptions.FilterConfig.
+// It is intended to release the resources built during OnConfigInit, such as stopping the background goroutines.
+//
+// OnConfigDestroy is called within its own goroutine, once the filter configuration has been replaced,
+// or destroyed by Envoy, and it is no longer used by any in-flight request.
+// Since Envoy doesn't notify the Go plugin about the configuration destruction, it relies on the Go garbage collector,
+// hence the call might be delayed until the next garbage collection cycle.
+type ConfigDestroyer interface {
+	OnConfigDestroy(c ConfigContext)
+}
+
+var _ ConfigContext = &configContext{}
+
+type configContext struct {
+	filterConfig interface{}
+	cache        Cache
+	metrics      Metrics
+	logger       logr.Logger
+}
+
+func (c *configContext) GetFilterConfig() interface{} { return c.filterConfig }
+func (c *configContext) GetCache() Cache              { return c.cache }
+func (c *configContext) Log() logr.Logger             { return c.logger }
+func (c *configContext) Metrics() Metrics             { return c.metrics }
+
+// configScope represents the lifetime of an initialized filter configuration.
+// It is referenced by the internalConfig, as well as by every request context,
+// hence once it is no longer reachable, the filter configuration is considered destroyed.
+//
+// The scope is intentionally a separate object from the filter configuration,
+// as the background goroutines started by the filter configuration would otherwise keep it reachable forever.
+type configScope struct {
+	ctx *configContext
+}
+
+// initFilterConfig calls the OnConfigInit hook of the given filter configuration, if any,
+// and returns a configScope that calls the OnConfigDestroy hook, if any, once it is no longer reachable.
+func (c *internalConfig) initFilterConfig(filterConfig interface{}) (*configScope, error) {
+	ctx := &configContext{
+		filterConfig: filterConfig,
+		cache:        c.internalCache,
+		metrics:      c.metricRegistry(),
+		logger:       c.logger.WithName("config"),
+	}
+
+	if initializer, ok := filterConfig.(ConfigInitializer); ok {
+		if err := initializer.OnConfigInit(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	destroyer, ok := filterConfig.(ConfigDestroyer)
+	if !ok {
+		return nil, nil
+	}
+
+	scope := &configScope{ctx: ctx}
+	runtime.SetFinalizer(scope, func(s *configScope) {
+		go destroyer.OnConfigDestroy(s.ctx)
+	})
+
+	return scope, nil
+}
+
+// configMerges holds the merged config of a child config along with the parent config it is merged with,
+// so that the merged filter config is initialized exactly once, instead of once per merge.
+// Once the parent config is replaced, a new merged config is built, while the previous one,
+// along with its scope, is released once it is no longer used by any in-flight request.
+type configMerges struct {
+	mu            sync.Mutex
+	parent        *internalConfig
+	parentVersion uint64
+	merged        *mergedConfig
+}
+
+type mergedConfig struct {
+	once   sync.Once
+	config *internalConfig
+	err    error
+}
+
+// of returns the merged config of the given parent config.
+func (m *configMerges) of(parent *internalConfig) *mergedConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.merged == nil || m.parent != parent || m.parentVersion != parent.version {
+		m.parent = parent
+		m.parentVersion = parent.version
+		m.merged = &mergedConfig{}
+	}
+
+	return m.merged
+}
@@ -0,0 +1,184 @@
+package gonvoy
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type lifecycleConfig struct {
+	Name    string `json:"name" envoy:"mergeable,preserve_root"`
+	Invalid bool   `json:"invalid" envoy:"mergeable"`
+
+	initialized string
+}
+
+// lifecycleDestroyed receives the name of the destroyed lifecycleConfig,
+// since the filter config is created from a fresh instance of ConfigOptions.FilterConfig type.
+var lifecycleDestroyed = make(chan string, 16)
+
+func (c *lifecycleConfig) OnConfigInit(cc ConfigContext) error {
+	if c.Invalid {
+		return errors.New("unable to init")
+	}
+
+	c.initialized = cc.GetFilterConfig().(*lifecycleConfig).Name
+	return nil
+}
+
+func (c *lifecycleConfig) OnConfigDestroy(cc ConfigContext) {
+	lifecycleDestroyed <- c.Name
+}
+
+type loggingConfig struct {
+	Name string `json:"name"`
+}
+
+func (c *loggingConfig) OnConfigInit(cc ConfigContext) error {
+	cc.Log().Info("initialized", "name", c.Name)
+	return nil
+}
+
+func TestConfigParser_Lifecycle(t *testing.T) {
+	t.Run("root config is initialized once parsed", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(lifecycleConfig)})
+
+		cfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"name": "root"}), newConfigCallbackHandler(t))
+		require.NoError(t, err)
+
+		ic := cfg.(*internalConfig)
+		assert.Equal(t, "root", ic.filterConfig.(*lifecycleConfig).initialized)
+		assert.NotNil(t, ic.scope)
+
+		c := &context{}
+		require.NoError(t, applyInternalConfig(c, ic))
+		assert.Same(t, ic.scope, c.configScope)
+	})
+
+	t.Run("omitted root config is initialized once parsed", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(lifecycleConfig)})
+
+		cfg, err := cp.Parse(&anypb.Any{}, newConfigCallbackHandler(t))
+		require.NoError(t, err)
+
+		ic := cfg.(*internalConfig)
+		assert.IsType(t, &lifecycleConfig{}, ic.filterConfig)
+		assert.NotNil(t, ic.scope)
+	})
+
+	t.Run("root config init logs with the config logger", func(t *testing.T) {
+		var logs []string
+		logger := funcr.New(func(prefix, args string) {
+			logs = append(logs, prefix+" "+args)
+		}, funcr.Options{})
+
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(loggingConfig), ConfigLogger: logger})
+
+		_, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"name": "root"}), newConfigCallbackHandler(t))
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Contains(t, logs[0], `"msg"="initialized" "name"="root"`)
+	})
+
+	t.Run("root config init failure rejects the config, and keeps the previous callbacks", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(lifecycleConfig)})
+
+		cc := newConfigCallbackHandler(t)
+		cfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"name": "root"}), cc)
+		require.NoError(t, err)
+
+		_, err = cp.Parse(newTestConfigAny(t, map[string]interface{}{"invalid": true}), newConfigCallbackHandler(t))
+		assert.ErrorContains(t, err, "configparser: init failed; unable to init")
+
+		ic := cfg.(*internalConfig)
+		assert.Same(t, cc, ic.callbacks)
+		assert.Equal(t, "root", ic.filterConfig.(*lifecycleConfig).Name)
+	})
+
+	t.Run("child config is initialized once merged", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(lifecycleConfig)})
+
+		parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"name": "root"}), newConfigCallbackHandler(t))
+		require.NoError(t, err)
+		childCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"invalid": false}), nil)
+		require.NoError(t, err)
+
+		assert.Empty(t, childCfg.(*internalConfig).filterConfig.(*lifecycleConfig).initialized)
+		assert.Nil(t, childCfg.(*internalConfig).scope)
+
+		merged := cp.Merge(parentCfg, childCfg).(*internalConfig)
+		assert.NoError(t, merged.configErr)
+		assert.Equal(t, "root", merged.filterConfig.(*lifecycleConfig).initialized)
+		assert.NotNil(t, merged.scope)
+		assert.NotSame(t, parentCfg.(*internalConfig).scope, merged.scope)
+	})
+
+	t.Run("child config is initialized once per parent config", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(lifecycleConfig)})
+
+		cc := newConfigCallbackHandler(t)
+		parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"name": "root"}), cc)
+		require.NoError(t, err)
+		childCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{}), nil)
+		require.NoError(t, err)
+
+		merged := cp.Merge(parentCfg, childCfg).(*internalConfig)
+		assert.Same(t, merged, cp.Merge(parentCfg, childCfg), "merged config must be initialized exactly once")
+		assert.Same(t, merged.scope, cp.Merge(parentCfg, childCfg).(*internalConfig).scope)
+		assert.Nil(t, childCfg.(*internalConfig).scope, "child config must be left intact")
+
+		newParentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"name": "reloaded"}), cc)
+		require.NoError(t, err)
+
+		remerged := cp.Merge(newParentCfg, childCfg).(*internalConfig)
+		assert.NotSame(t, merged, remerged)
+		assert.NotSame(t, merged.scope, remerged.scope)
+		assert.Equal(t, "root", merged.filterConfig.(*lifecycleConfig).initialized)
+		assert.Equal(t, "reloaded", remerged.filterConfig.(*lifecycleConfig).initialized)
+	})
+
+	t.Run("merged config init failure is kept", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(lifecycleConfig)})
+
+		parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"name": "root"}), newConfigCallbackHandler(t))
+		require.NoError(t, err)
+		childCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"invalid": true}), nil)
+		require.NoError(t, err)
+
+		merged := cp.Merge(parentCfg, childCfg).(*internalConfig)
+		assert.ErrorContains(t, merged.configErr, "merged config init failed; unable to init")
+	})
+
+	t.Run("replaced config is destroyed once no longer referenced", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(lifecycleConfig)})
+
+		cc := newConfigCallbackHandler(t)
+		_, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"name": "first"}), cc)
+		require.NoError(t, err)
+
+		_, err = cp.Parse(newTestConfigAny(t, map[string]interface{}{"name": "second"}), cc)
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			runtime.GC()
+
+			// configs from the previous subtests might be destroyed as well
+			for {
+				select {
+				case name := <-lifecycleDestroyed:
+					if name == "first" {
+						return true
+					}
+				default:
+					return false
+				}
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
@@ -1,11 +1,13 @@
 package gonvoy
 
 import (
+	"sync"
 	"testing"
 
 	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConfiguration_Metrics(t *testing.T) {
@@ -26,3 +28,65 @@ func TestConfiguration_Metrics(t *testing.T) {
 	gc.defineCounterMetric("foo_key=value_key1=value2")
 	gc.defineGaugeMetric("foo_key=value_key1=value2")
 }
+
+func TestConfiguration_MetricsWithoutRegistry(t *testing.T) {
+	t.Run("config without the metric registry", func(t *testing.T) {
+		fc := mock_envoy.NewFilterCallbackHandler(t)
+		c, err := NewContext(fc, contextOptions{config: &internalConfig{}})
+		require.NoError(t, err)
+		require.NotNil(t, c.Metrics())
+
+		assert.NotPanics(t, func() {
+			c.Metrics().Counter("requests_total", "host", "example.com").Increment(1)
+			c.Metrics().Gauge("active_requests").Increment(1)
+			c.Metrics().Histogram("latency").Record(10)
+		})
+	})
+
+	t.Run("config without the config callbacks", func(t *testing.T) {
+		gc := newInternalConfig(ConfigOptions{})
+		fc := mock_envoy.NewFilterCallbackHandler(t)
+		c, err := NewContext(fc, contextOptions{config: gc})
+		require.NoError(t, err)
+
+		assert.NotPanics(t, func() {
+			c.Metrics().Counter("requests_total").Increment(1)
+			c.Metrics().Histogram("latency").Record(10)
+		})
+	})
+}
+
+func TestConfiguration_SetCallbacksConcurrently(t *testing.T) {
+	newCallbacks := func() *mock_envoy.ConfigCallbackHandler {
+		cc := mock_envoy.NewConfigCallbackHandler(t)
+		cc.EXPECT().DefineCounterMetric(mock.Anything).Return(noopMetric{}).Maybe()
+		return cc
+	}
+
+	gc := newInternalConfig(ConfigOptions{})
+	gc.setCallbacks(newCallbacks())
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					gc.metrics.Counter("requests_total").Increment(1)
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 1000; i++ {
+		gc.setCallbacks(newCallbacks())
+	}
+
+	close(done)
+	wg.Wait()
+}
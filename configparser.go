@@ -4,10 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
-	"strings"
+	"time"
 
 	"github.com/ardikabs/gonvoy/pkg/util"
 	xds "github.com/cncf/xds/go/xds/type/v3"
+	"github.com/go-logr/logr"
 	"github.com/tidwall/gjson"
 
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
@@ -16,20 +17,88 @@ import (
 
 // ConfigOptions represents the configuration options for the filters.
 type ConfigOptions struct {
+	// FilterName specifies the name of the filter, which is used as the default namespace of the dynamic metadata, see RuntimeContext.DynamicMetadata.
+	// It is set to the registered filter name by the envoy.RegisterHttpFilter, unless it is already specified.
+	//
+	FilterName string
+
 	// FilterConfig represents the filter configuration.
+	// When the filter configuration implements `Validate() error`, it is validated once at the config-load time,
+	// either during the parse, or once merged for the child filter configuration.
+	//
+	// The `default:"..."` struct tags are applied to the unset fields, e.g., `default:"5s"` for a time.Duration field,
+	// where the map defaults are only applied when the filter configuration leaves the map unset.
+	// When the filter configuration is omitted, the filter configuration is built from its defaults.
+	// Hence, a child filter configuration field is considered unset for the `preserve_root`, `append`, and `deep` merges
+	// only when its key is absent, so that it can be set back to its default or zero value, e.g., `false` on a `default:"true"` field.
+	//
+	// The merge behavior of the route-level (child) filter configuration is controlled by the `envoy:"..."` struct tags,
+	// such as `envoy:"mergeable"`, `envoy:"mergeable,preserve_root"`, `envoy:"mergeable,append"`, `envoy:"mergeable,deep"`, and `envoy:"replace"`.
+	// When FilterConfig is unset, the child JSON object values replace the parent JSON object values,
+	// while the parent top-level keys absent on the child are kept, see MergeStrategies.
 	FilterConfig interface{}
 
+	// MergeStrategies specifies the merge behavior of the route-level (child) filter configuration when FilterConfig is unset,
+	// keyed by the dot-separated path of the JSON object key, e.g., `{"upstream.headers": "mergeable,deep", "hosts": "mergeable,append"}`.
+	// The values follow the `envoy:"..."` struct tags, where `deep` recursively merges the child object into the parent object,
+	// and its nested objects follow their own path, whereas `append` appends the child array to the parent array.
+	// A key without a merge strategy is replaced by the child value.
+	MergeStrategies map[string]string
+
 	// AlwaysUseChildConfig intend to disable merge behavior, ensuring that it always references the child filter configuration.
 	//
 	AlwaysUseChildConfig bool
 
 	// IgnoreMergeError specifies during a merge error, instead of panicking, it will fallback to the root configuration.
+	// Likewise, when the merged configuration fails the validation, it will fallback to the root configuration.
+	// Otherwise, the invalid merged configuration causes the filter to be ignored on every request of the route.
 	//
 	IgnoreMergeError bool
 
 	// MetricsPrefix specifies the prefix used for metrics.
 	MetricsPrefix string
 
+	// CacheCapacity specifies the maximum number of entries held by the internal cache, see Cache.
+	// Once exceeded, the least recently used entry is evicted.
+	// It defaults to zero, meaning the cache is unbounded.
+	CacheCapacity int
+
+	// CacheDefaultTTL specifies the time-to-live of the internal cache entries stored through Cache.Store or Cache.LoadOrStore.
+	// It defaults to zero, meaning the entries never expire.
+	CacheDefaultTTL time.Duration
+
+	// EnableConfigInterpolation specifies whether the placeholders within the string values of the filter configuration are replaced, such as:
+	//   - `${VAR}` with the value of the VAR environment variable, or `${VAR:-fallback}` with a fallback value.
+	//   - `${file:/path/to/file}` with the content of the file, e.g., a mounted secret.
+	//   - `$${...}` is an escaped placeholder, it is replaced with the literal `${...}`.
+	//
+	// It keeps secrets and per-environment values out of the Envoy configuration.
+	// The filter configuration is rejected if a placeholder can't be resolved.
+	EnableConfigInterpolation bool
+
+	// HistogramBuckets specifies the upper bounds of the buckets used by every histogram metric.
+	// It defaults to DefaultHistogramBuckets, which are tailored for request latencies measured in milliseconds.
+	HistogramBuckets []uint64
+
+	// ConfigLogger specifies the logger given to the filter configuration lifecycle hooks, see ConfigContext.Log.
+	// It defaults to the Envoy log, which is only available within the Envoy process,
+	// hence a custom logger is required to run the lifecycle hooks on unit-test, e.g., logr.Discard().
+	ConfigLogger logr.Logger
+
+	// ErrorRenderer specifies how the error responses are rendered, see ErrorRenderer.
+	// It defaults to DefaultErrorRenderer, which renders the error response negotiated from the request Accept header, see NegotiatedErrorRenderer.
+	// Use ProblemJSONErrorRenderer to render the problem details JSON response (RFC 7807).
+	ErrorRenderer ErrorRenderer
+
+	// Renderers specifies the renderers of the negotiated responses keyed by their media type, see Context.Negotiate.
+	// The renderer is selected based on the request Accept header, and it also applies to the error responses rendered by the NegotiatedErrorRenderer.
+	// It defaults to DefaultRenderers, which render JSON, XML, HTML, and plain text.
+	Renderers map[string]Renderer
+
+	// NegotiationFallback specifies the media type used when the request Accept header is empty, or none of the Renderers is acceptable.
+	// It defaults to MIMEApplicationJSON, and it is rendered as JSON unless the media type is one of the Renderers.
+	NegotiationFallback string
+
 	// AutoReloadRoute specifies whether the route should be auto reloaded when the request headers changes.
 	// It recommends to set this to true when the filter is used in a route configuration and the route is expected to change dynamically within certain conditions.
 	AutoReloadRoute bool
@@ -94,56 +163,128 @@ type ConfigOptions struct {
 	// Therefore turning this on will preserve the Content-Length header.
 	//
 	DisableChunkedEncodingResponse bool
+
+	// EnableRequestBodyStreaming specifies whether the HTTP Request body is processed chunk by chunk as it arrives,
+	// instead of being entirely buffered in memory before the OnRequestBody phase.
+	// This setting applies when either EnableRequestBodyRead or EnableRequestBodyWrite is enabled.
+	// When enabled, handlers that implement HttpFilterChunkHandler receive each chunk through OnRequestChunk,
+	// while OnRequestBody is never called and RequestBody is not available.
+	//
+	// Since the request headers are no longer buffered, the Content-Length header is always removed when the request body is writable,
+	// hence DisableChunkedEncodingRequest has no effect.
+	EnableRequestBodyStreaming bool
+
+	// EnableResponseBodyStreaming specifies whether the HTTP Response body is processed chunk by chunk as it arrives,
+	// instead of being entirely buffered in memory before the OnResponseBody phase.
+	// This setting applies when either EnableResponseBodyRead or EnableResponseBodyWrite is enabled.
+	// When enabled, handlers that implement HttpFilterChunkHandler receive each chunk through OnResponseChunk,
+	// while OnResponseBody is never called and ResponseBody is not available.
+	//
+	// Since the response headers are sent to the downstream before the response body,
+	// the filter can no longer respond with a custom error response during OnResponseChunk.
+	// Likewise, the Content-Length header is always removed when the response body is writable,
+	// hence DisableChunkedEncodingResponse has no effect.
+	EnableResponseBodyStreaming bool
 }
 
 type configParser struct {
 	options          ConfigOptions
 	rootGlobalConfig *internalConfig
+
+	// literalStrategies holds the parsed MergeStrategies, keyed by the JSON object path.
+	literalStrategies map[string]mergeStrategy
 }
 
 func NewConfigParser(options ConfigOptions) api.StreamFilterConfigParser {
+	literalStrategies := make(map[string]mergeStrategy, len(options.MergeStrategies))
+	for path, tag := range options.MergeStrategies {
+		literalStrategies[path] = parseMergeTag(tag)
+	}
+
 	return &configParser{
-		options:          options,
-		rootGlobalConfig: newInternalConfig(options),
+		options:           options,
+		rootGlobalConfig:  newInternalConfig(options),
+		literalStrategies: literalStrategies,
 	}
 }
 
 func (p *configParser) Parse(any *anypb.Any, callbacks api.ConfigCallbackHandler) (interface{}, error) {
 	// Parse the filter configuration if it is provided
-	filterConfig, err := p.parseFilterConfig(any)
+	filterConfig, filterConfigKeys, err := p.parseFilterConfig(any)
 	if err != nil {
 		return nil, err
 	}
 
 	// Handle the root (parent) plugin configuration
-	if callbacks != nil {
-		// Renew the config callbacks and filter config once root plugin configuration updated
-		p.rootGlobalConfig.callbacks = callbacks
-		p.rootGlobalConfig.filterConfig = filterConfig
-		return p.rootGlobalConfig, nil
+	isRoot := callbacks != nil
+
+	// Validate the filter configuration, so a bad configuration is rejected at the config-load time.
+	// Unless AlwaysUseChildConfig is enabled, a child filter config might be partial,
+	// hence it is validated once merged with the parent filter config instead.
+	if isRoot || p.options.AlwaysUseChildConfig {
+		if err := validateFilterConfig(filterConfig); err != nil {
+			return nil, fmt.Errorf("configparser: validation failed; %w", err)
+		}
+	}
+
+	if isRoot {
+		root := p.rootGlobalConfig
+
+		// Renew the config callbacks and filter config once root plugin configuration updated.
+		// The metrics are defined again against the renewed config callbacks, see internalConfig.setCallbacks.
+		prevCallbacks := root.callbacks
+		root.setCallbacks(callbacks)
+
+		scope, err := root.initFilterConfig(filterConfig)
+		if err != nil {
+			// Envoy keeps the previous configuration once rejected, so are the config callbacks.
+			root.setCallbacks(prevCallbacks)
+
+			return nil, fmt.Errorf("configparser: init failed; %w", err)
+		}
+
+		// The previous scope, if any, is released once it is no longer used by any in-flight request.
+		root.filterConfig = filterConfig
+		root.scope = scope
+		root.version++
+		return root, nil
 	}
 
 	// Create a copy of the root global config for the child filter config
-	// This shares all attributes except the filter config
+	// This shares all attributes except the filter config and its scope
 	copyGlobalConfig := *p.rootGlobalConfig
 	copyGlobalConfig.filterConfig = filterConfig
-	return &copyGlobalConfig, nil
-}
+	copyGlobalConfig.filterConfigKeys = filterConfigKeys
+	copyGlobalConfig.scope = nil
+	copyGlobalConfig.merges = &configMerges{}
 
-func (p *configParser) parseFilterConfig(any *anypb.Any) (filterCfg interface{}, err error) {
-	if any.GetValue() == nil {
-		return nil, nil
-	}
+	// Unless AlwaysUseChildConfig is enabled, the child filter config is initialized once merged.
+	if p.options.AlwaysUseChildConfig {
+		scope, err := copyGlobalConfig.initFilterConfig(filterConfig)
+		if err != nil {
+			return nil, fmt.Errorf("configparser: init failed; %w", err)
+		}
 
-	configStruct := &xds.TypedStruct{}
-	if err := any.UnmarshalTo(configStruct); err != nil {
-		return nil, fmt.Errorf("configparser: parse failed; %w", err)
+		copyGlobalConfig.scope = scope
 	}
 
-	v := configStruct.Value
-	b, err := v.MarshalJSON()
-	if err != nil {
-		return nil, fmt.Errorf("configparser: parse failed; %w", err)
+	return &copyGlobalConfig, nil
+}
+
+// parseFilterConfig returns the filter config, along with the keys given on it when FilterConfig is set, see fieldPresence.
+// When the filter config is omitted, e.g., the typed_config value is absent, the FilterConfig is built from its defaults,
+// so that it is validated and initialized like any given filter config, instead of once per request.
+func (p *configParser) parseFilterConfig(any *anypb.Any) (filterCfg interface{}, keys fieldPresence, err error) {
+	b := []byte("{}")
+	switch {
+	case any.GetValue() != nil:
+		b, err = p.marshalFilterConfig(any)
+		if err != nil {
+			return nil, nil, err
+		}
+
+	case util.IsNil(p.options.FilterConfig):
+		return nil, nil, nil
 	}
 
 	if util.IsNil(p.options.FilterConfig) {
@@ -151,15 +292,51 @@ func (p *configParser) parseFilterConfig(any *anypb.Any) (filterCfg interface{},
 	} else {
 		filterCfg, err = util.NewFrom(p.options.FilterConfig)
 		if err != nil {
-			return nil, fmt.Errorf("configparser: parse failed; %w", err)
+			return nil, nil, fmt.Errorf("configparser: parse failed; %w", err)
+		}
+
+		// The defaults are applied prior to the unmarshalling, so the values given on the filter configuration take precedence,
+		// including the ones explicitly set to zero, e.g., `false` on a `default:"true"` field.
+		if err := applyDefaultsOf(filterCfg, isNotMapType); err != nil {
+			return nil, nil, fmt.Errorf("configparser: apply defaults failed; %w", err)
 		}
 
 		if err := json.Unmarshal(b, &filterCfg); err != nil {
-			return nil, fmt.Errorf("configparser: parse failed; %w", err)
+			return nil, nil, fmt.Errorf("configparser: parse failed; %w", err)
+		}
+
+		// Except the map defaults, since the unmarshalling merges into an existing map,
+		// hence they are applied afterwards, only to the maps left unset.
+		if err := applyDefaultsOf(filterCfg, isMapType); err != nil {
+			return nil, nil, fmt.Errorf("configparser: apply defaults failed; %w", err)
+		}
+
+		keys = newFieldPresence(gjson.ParseBytes(b))
+	}
+
+	return filterCfg, keys, nil
+}
+
+// marshalFilterConfig returns the JSON encoding of the TypedStruct value, with its placeholders replaced if enabled.
+func (p *configParser) marshalFilterConfig(any *anypb.Any) ([]byte, error) {
+	configStruct := &xds.TypedStruct{}
+	if err := any.UnmarshalTo(configStruct); err != nil {
+		return nil, fmt.Errorf("configparser: parse failed; %w", err)
+	}
+
+	v := configStruct.Value
+	if p.options.EnableConfigInterpolation {
+		if err := interpolateStruct(v); err != nil {
+			return nil, fmt.Errorf("configparser: interpolation failed; %w", err)
 		}
 	}
 
-	return filterCfg, nil
+	b, err := v.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("configparser: parse failed; %w", err)
+	}
+
+	return b, nil
 }
 
 func (p *configParser) Merge(parent, child interface{}) interface{} {
@@ -170,22 +347,68 @@ func (p *configParser) Merge(parent, child interface{}) interface{} {
 		panic("configparser: merge failed; both parent and child configs uses unknown data types")
 	}
 
+	// Envoy merges the child filter config lazily on the worker threads,
+	// hence the merged filter config is built and initialized exactly once per parent and child pair, see configMerges.
+	merged := origChildGlobalConfig.merges.of(origParentGlobalConfig)
+	merged.once.Do(func() {
+		merged.config, merged.err = p.mergeConfig(origParentGlobalConfig, origChildGlobalConfig)
+	})
+
+	if merged.err != nil {
+		panic(merged.err)
+	}
+
+	return merged.config
+}
+
+// mergeConfig returns a new internalConfig holding the merged filter config, while the parent and child configs are left intact.
+func (p *configParser) mergeConfig(parent, child *internalConfig) (*internalConfig, error) {
+	merged := *child
+	merged.merges = nil
+
 	if util.IsNil(p.options.FilterConfig) {
-		origChildGlobalConfig.filterConfig = p.mergeLiteral(origParentGlobalConfig.filterConfig, origChildGlobalConfig.filterConfig)
-		return origChildGlobalConfig
+		merged.filterConfig = p.mergeLiteral(parent.filterConfig, child.filterConfig)
+		return &merged, nil
 	}
 
-	mergedFilterCfg, err := p.mergeStruct(origParentGlobalConfig.filterConfig, origChildGlobalConfig.filterConfig)
+	mergedFilterCfg, err := p.mergeStruct(parent.filterConfig, child.filterConfig, child.filterConfigKeys)
 	if err != nil {
 		if p.options.IgnoreMergeError {
-			return origParentGlobalConfig
+			return parent, nil
 		}
 
-		panic(err)
+		return nil, err
 	}
 
-	origChildGlobalConfig.filterConfig = mergedFilterCfg
-	return origChildGlobalConfig
+	// The child filter config has been validated and initialized during the parse when AlwaysUseChildConfig is enabled,
+	// since the merged filter config is the child filter config itself.
+	if p.options.AlwaysUseChildConfig {
+		return child, nil
+	}
+
+	// Validate the merged filter config once, and keep the result,
+	// so that the request path neither pays for the validation nor proceeds with an invalid config.
+	merged.filterConfig = mergedFilterCfg
+	if err := validateFilterConfig(mergedFilterCfg); err != nil {
+		if p.options.IgnoreMergeError {
+			return parent, nil
+		}
+
+		merged.configErr = fmt.Errorf("configparser: merge failed; merged config is invalid; %w", err)
+		return &merged, nil
+	}
+
+	scope, err := merged.initFilterConfig(mergedFilterCfg)
+	if err != nil {
+		if p.options.IgnoreMergeError {
+			return parent, nil
+		}
+
+		merged.configErr = fmt.Errorf("configparser: merge failed; merged config init failed; %w", err)
+	}
+
+	merged.scope = scope
+	return &merged, nil
 }
 
 func (p *configParser) mergeLiteral(parent, child interface{}) gjson.Result {
@@ -201,13 +424,7 @@ func (p *configParser) mergeLiteral(parent, child interface{}) gjson.Result {
 		return gjson.Result{}
 	}
 
-	for k, v := range parentJSONMap {
-		if _, ok := childJSONMap[k]; !ok {
-			childJSONMap[k] = v
-		}
-	}
-
-	jsonBytes, err := json.Marshal(childJSONMap)
+	jsonBytes, err := json.Marshal(mergeJSONObject(parentJSONMap, childJSONMap, p.literalStrategies, ""))
 	if err != nil {
 		return gjson.Result{}
 	}
@@ -215,7 +432,7 @@ func (p *configParser) mergeLiteral(parent, child interface{}) gjson.Result {
 	return gjson.ParseBytes(jsonBytes)
 }
 
-func (p *configParser) mergeStruct(parent, child interface{}) (interface{}, error) {
+func (p *configParser) mergeStruct(parent, child interface{}, childKeys fieldPresence) (interface{}, error) {
 	origParentPtr := reflect.ValueOf(parent)
 	origParentValue := origParentPtr.Elem()
 
@@ -243,26 +460,22 @@ func (p *configParser) mergeStruct(parent, child interface{}) (interface{}, erro
 	}
 
 	parentValue.Set(origParentValue)
+	mergeFields(parentValue, childValue, false, childKeys)
 
-	for i := 0; i < childValue.NumField(); i++ {
-		tags, ok := parentType.Field(i).Tag.Lookup("envoy")
-		if !ok {
-			continue
-		}
-
-		v := childValue.Field(i)
+	return parentPtr.Interface(), nil
+}
 
-		isValidField := v.IsValid() || v.CanSet()
-		isMergeable := strings.Contains(tags, "mergeable")
-		isPreserveable := strings.Contains(tags, "preserve_root") && v.IsZero()
-		if !isValidField ||
-			!isMergeable ||
-			isPreserveable {
-			continue
-		}
+// isUnsetField reports whether the field value is considered unset for the defaults, either it is a zero value,
+// or it already equals to the default value given by the `default` struct tag, so that the defaults are idempotent.
+func isUnsetField(field reflect.StructField, v reflect.Value) bool {
+	if v.IsZero() {
+		return true
+	}
 
-		parentValue.Field(i).Set(v)
+	defaultValue, ok, err := defaultValueOf(field)
+	if !ok || err != nil {
+		return false
 	}
 
-	return parentPtr.Interface(), nil
+	return reflect.DeepEqual(v.Interface(), defaultValue.Interface())
 }
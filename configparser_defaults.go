@@ -0,0 +1,236 @@
+package gonvoy
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultTagName = "default"
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// applyDefaults sets the unset fields of the given struct pointer from their `default:"..."` struct tags, see isUnsetField.
+//
+// The supported tag values are:
+//   - scalars, e.g., `default:"foo"`, `default:"true"`, `default:"10"`, `default:"0.5"`.
+//   - durations, e.g., `default:"5s"`.
+//   - slices, either comma-separated, e.g., `default:"a,b,c"`, or a JSON array, e.g., `default:"[1,2,3]"`.
+//   - maps, either comma-separated key=value pairs, e.g., `default:"a=1,b=2"`, or a JSON object.
+//   - structs, as a JSON object, e.g., `default:"{\"a\":\"foo\"}"`.
+//   - any type that implements encoding.TextUnmarshaler.
+//
+// Nested structs are traversed to apply their defaults, including pointer to structs that carry a default tag, e.g., `default:"{}"`.
+func applyDefaults(ptr interface{}) error {
+	return applyDefaultsOf(ptr, nil)
+}
+
+// defaultsFilter reports whether the default of a field type is applied, a nil defaultsFilter applies all of them.
+type defaultsFilter func(t reflect.Type) bool
+
+func isMapType(t reflect.Type) bool    { return t.Kind() == reflect.Map }
+func isNotMapType(t reflect.Type) bool { return t.Kind() != reflect.Map }
+
+// applyDefaultsOf is applyDefaults, restricted to the fields whose type is accepted by the filter.
+func applyDefaultsOf(ptr interface{}, filter defaultsFilter) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil
+	}
+
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return applyStructDefaults(v, filter)
+}
+
+func applyStructDefaults(v reflect.Value, filter defaultsFilter) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fv := v.Field(i)
+		tag, hasTag := field.Tag.Lookup(defaultTagName)
+		if hasTag && (filter == nil || filter(field.Type)) && isUnsetField(field, fv) {
+			if err := setDefaultValue(fv, tag); err != nil {
+				return fmt.Errorf("field %s; %w", field.Name, err)
+			}
+		}
+
+		if err := applyNestedDefaults(fv, filter); err != nil {
+			return fmt.Errorf("field %s; %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func applyNestedDefaults(fv reflect.Value, filter defaultsFilter) error {
+	switch {
+	case fv.Kind() == reflect.Struct && !isTextUnmarshaler(fv.Type()):
+		return applyStructDefaults(fv, filter)
+	case fv.Kind() == reflect.Pointer && !fv.IsNil() && fv.Elem().Kind() == reflect.Struct && !isTextUnmarshaler(fv.Type()):
+		return applyStructDefaults(fv.Elem(), filter)
+	}
+
+	return nil
+}
+
+// defaultValueOf returns the default value of the struct field, and reports whether the field has a default tag.
+func defaultValueOf(field reflect.StructField) (reflect.Value, bool, error) {
+	tag, ok := field.Tag.Lookup(defaultTagName)
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+
+	v := reflect.New(field.Type).Elem()
+	if err := setDefaultValue(v, tag); err != nil {
+		return reflect.Value{}, true, err
+	}
+
+	if err := applyNestedDefaults(v, nil); err != nil {
+		return reflect.Value{}, true, err
+	}
+
+	return v, true, nil
+}
+
+func setDefaultValue(v reflect.Value, tag string) error {
+	if isTextUnmarshaler(v.Type()) {
+		if v.Kind() == reflect.Pointer {
+			v.Set(reflect.New(v.Type().Elem()))
+			return v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(tag))
+		}
+
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(tag))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(tag)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(tag)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(tag)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(tag, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(tag, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(tag, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+
+	case reflect.Slice:
+		if strings.HasPrefix(strings.TrimSpace(tag), "[") {
+			return json.Unmarshal([]byte(tag), v.Addr().Interface())
+		}
+
+		items := splitDefaultList(tag)
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setDefaultValue(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+
+	case reflect.Map:
+		if strings.HasPrefix(strings.TrimSpace(tag), "{") {
+			return json.Unmarshal([]byte(tag), v.Addr().Interface())
+		}
+
+		m := reflect.MakeMap(v.Type())
+		for _, item := range splitDefaultList(tag) {
+			key, value, found := strings.Cut(item, "=")
+			if !found {
+				return fmt.Errorf("invalid map entry %q, expecting key=value", item)
+			}
+
+			kv := reflect.New(v.Type().Key()).Elem()
+			if err := setDefaultValue(kv, strings.TrimSpace(key)); err != nil {
+				return err
+			}
+
+			vv := reflect.New(v.Type().Elem()).Elem()
+			if err := setDefaultValue(vv, strings.TrimSpace(value)); err != nil {
+				return err
+			}
+
+			m.SetMapIndex(kv, vv)
+		}
+		v.Set(m)
+
+	case reflect.Struct:
+		return json.Unmarshal([]byte(tag), v.Addr().Interface())
+
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if tag != "" {
+			if err := setDefaultValue(elem.Elem(), tag); err != nil {
+				return err
+			}
+		}
+		v.Set(elem)
+
+	default:
+		return fmt.Errorf("unsupported default value for type %s", v.Type())
+	}
+
+	return nil
+}
+
+func splitDefaultList(tag string) []string {
+	if strings.TrimSpace(tag) == "" {
+		return nil
+	}
+
+	items := strings.Split(tag, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+
+	return items
+}
+
+func isTextUnmarshaler(t reflect.Type) bool {
+	return t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
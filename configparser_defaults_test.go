@@ -0,0 +1,228 @@
+package gonvoy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type defaultsNested struct {
+	Name    string        `json:"name" default:"nested"`
+	Timeout time.Duration `json:"timeout" default:"1s"`
+}
+
+type defaultsConfig struct {
+	String   string            `json:"string" default:"foo"`
+	Bool     bool              `json:"bool" default:"true"`
+	Int      int               `json:"int" default:"10"`
+	Uint     uint32            `json:"uint" default:"20"`
+	Float    float64           `json:"float" default:"0.5"`
+	Duration time.Duration     `json:"duration" default:"5s"`
+	Time     time.Time         `json:"time" default:"2024-01-02T03:04:05Z"`
+	Slice    []string          `json:"slice" default:"a, b,c"`
+	IntSlice []int             `json:"intSlice" default:"[1,2,3]"`
+	Map      map[string]int    `json:"map" default:"a=1,b=2"`
+	JSONMap  map[string]string `json:"jsonMap" default:"{\"a\":\"b\"}"`
+	Pointer  *int              `json:"pointer" default:"7"`
+
+	Nested        defaultsNested  `json:"nested"`
+	NestedPointer *defaultsNested `json:"nestedPointer" default:"{}"`
+	NilPointer    *defaultsNested `json:"nilPointer"`
+	Struct        defaultsNested  `json:"struct" default:"{\"name\":\"from-tag\"}"`
+
+	Untagged string `json:"untagged"`
+	private  string `default:"ignored"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	cfg := &defaultsConfig{Int: 99}
+	require.NoError(t, applyDefaults(cfg))
+
+	assert.Equal(t, "foo", cfg.String)
+	assert.True(t, cfg.Bool)
+	assert.Equal(t, 99, cfg.Int, "non-zero value must be kept")
+	assert.Equal(t, uint32(20), cfg.Uint)
+	assert.Equal(t, 0.5, cfg.Float)
+	assert.Equal(t, 5*time.Second, cfg.Duration)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), cfg.Time)
+	assert.Equal(t, []string{"a", "b", "c"}, cfg.Slice)
+	assert.Equal(t, []int{1, 2, 3}, cfg.IntSlice)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, cfg.Map)
+	assert.Equal(t, map[string]string{"a": "b"}, cfg.JSONMap)
+	require.NotNil(t, cfg.Pointer)
+	assert.Equal(t, 7, *cfg.Pointer)
+
+	assert.Equal(t, defaultsNested{Name: "nested", Timeout: time.Second}, cfg.Nested)
+	assert.Equal(t, &defaultsNested{Name: "nested", Timeout: time.Second}, cfg.NestedPointer)
+	assert.Nil(t, cfg.NilPointer)
+	assert.Equal(t, defaultsNested{Name: "from-tag", Timeout: time.Second}, cfg.Struct)
+
+	assert.Empty(t, cfg.Untagged)
+	assert.Empty(t, cfg.private)
+}
+
+func TestApplyDefaults_UserSuppliedCollections(t *testing.T) {
+	cfg := &defaultsConfig{
+		Slice: []string{"x"},
+		Map:   map[string]int{"c": 3},
+	}
+
+	require.NoError(t, applyDefaults(cfg))
+	require.NoError(t, applyDefaults(cfg), "defaults must be idempotent")
+
+	assert.Equal(t, []string{"x"}, cfg.Slice)
+	assert.Equal(t, map[string]int{"c": 3}, cfg.Map)
+	assert.Equal(t, []int{1, 2, 3}, cfg.IntSlice)
+	assert.Equal(t, map[string]string{"a": "b"}, cfg.JSONMap)
+}
+
+func TestApplyDefaults_InvalidTag(t *testing.T) {
+	type invalid struct {
+		Duration time.Duration `default:"five seconds"`
+	}
+
+	err := applyDefaults(&invalid{})
+	assert.ErrorContains(t, err, "field Duration")
+}
+
+func TestConfigParser_Defaults(t *testing.T) {
+	type config struct {
+		Name    string        `json:"name" default:"gonvoy"`
+		Timeout time.Duration `json:"timeout" default:"5s" envoy:"mergeable,preserve_root"`
+		Retries int           `json:"retries" default:"3" envoy:"mergeable"`
+		Enabled bool          `json:"enabled" default:"true" envoy:"mergeable,preserve_root"`
+	}
+
+	mockCC := newConfigCallbackHandler(t)
+	cp := NewConfigParser(ConfigOptions{FilterConfig: new(config)})
+
+	parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"timeout": int64(10 * time.Second)}), mockCC)
+	require.NoError(t, err)
+
+	parent := parentCfg.(*internalConfig).filterConfig.(*config)
+	assert.Equal(t, "gonvoy", parent.Name)
+	assert.Equal(t, 10*time.Second, parent.Timeout)
+	assert.Equal(t, 3, parent.Retries)
+	assert.True(t, parent.Enabled)
+
+	t.Run("absent child values keep the parent values", func(t *testing.T) {
+		childCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"retries": 5}), nil)
+		require.NoError(t, err)
+
+		merged := cp.Merge(parentCfg, childCfg).(*internalConfig).filterConfig.(*config)
+		assert.Equal(t, 10*time.Second, merged.Timeout, "child default value of an absent key must not override the parent value")
+		assert.Equal(t, 5, merged.Retries)
+		assert.True(t, merged.Enabled)
+	})
+
+	t.Run("child values set back to the default or zero value are merged", func(t *testing.T) {
+		childCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{
+			"timeout": int64(5 * time.Second),
+			"enabled": false,
+		}), nil)
+		require.NoError(t, err)
+
+		merged := cp.Merge(parentCfg, childCfg).(*internalConfig).filterConfig.(*config)
+		assert.Equal(t, 5*time.Second, merged.Timeout)
+		assert.False(t, merged.Enabled)
+	})
+}
+
+func TestConfigParser_DefaultsOfCollections(t *testing.T) {
+	type config struct {
+		Enabled bool           `json:"enabled" default:"true"`
+		Hosts   []string       `json:"hosts" default:"a,b" envoy:"mergeable,append"`
+		Labels  map[string]int `json:"labels" default:"a=1,b=2" envoy:"mergeable,deep"`
+	}
+
+	t.Run("user supplied values are kept as they are", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(config)})
+		parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{
+			"enabled": false,
+			"hosts":   []interface{}{"c"},
+			"labels":  map[string]interface{}{"c": 3},
+		}), newConfigCallbackHandler(t))
+		require.NoError(t, err)
+
+		parent := parentCfg.(*internalConfig).filterConfig.(*config)
+		assert.False(t, parent.Enabled, "explicit zero value must be kept")
+		assert.Equal(t, []string{"c"}, parent.Hosts)
+		assert.Equal(t, map[string]int{"c": 3}, parent.Labels, "default map entries must not be merged into the user map")
+	})
+
+	t.Run("unset values are defaulted", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(config)})
+		parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{}), newConfigCallbackHandler(t))
+		require.NoError(t, err)
+
+		parent := parentCfg.(*internalConfig).filterConfig.(*config)
+		assert.True(t, parent.Enabled)
+		assert.Equal(t, []string{"a", "b"}, parent.Hosts)
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, parent.Labels)
+	})
+
+	t.Run("omitted config is built from the defaults", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(config)})
+		parentCfg, err := cp.Parse(&anypb.Any{}, newConfigCallbackHandler(t))
+		require.NoError(t, err)
+
+		assert.Equal(t, &config{
+			Enabled: true,
+			Hosts:   []string{"a", "b"},
+			Labels:  map[string]int{"a": 1, "b": 2},
+		}, parentCfg.(*internalConfig).filterConfig)
+	})
+
+	t.Run("child default values are not merged", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(config)})
+		parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{
+			"labels": map[string]interface{}{"a": 10},
+		}), newConfigCallbackHandler(t))
+		require.NoError(t, err)
+
+		childCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"enabled": true}), nil)
+		require.NoError(t, err)
+
+		merged := cp.Merge(parentCfg, childCfg).(*internalConfig).filterConfig.(*config)
+		assert.Equal(t, []string{"a", "b"}, merged.Hosts, "default slice must not be appended twice")
+		assert.Equal(t, map[string]int{"a": 10}, merged.Labels, "default map entries must not override the parent entries")
+	})
+
+	t.Run("child values are merged", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(config)})
+		parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{}), newConfigCallbackHandler(t))
+		require.NoError(t, err)
+
+		childCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{
+			"hosts":  []interface{}{"c"},
+			"labels": map[string]interface{}{"b": 20},
+		}), nil)
+		require.NoError(t, err)
+
+		merged := cp.Merge(parentCfg, childCfg).(*internalConfig).filterConfig.(*config)
+		assert.Equal(t, []string{"a", "b", "c"}, merged.Hosts)
+		assert.Equal(t, map[string]int{"a": 1, "b": 20}, merged.Labels)
+	})
+
+	t.Run("child default values given explicitly are merged", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(config)})
+		parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{
+			"hosts":  []interface{}{"c"},
+			"labels": map[string]interface{}{"a": 10},
+		}), newConfigCallbackHandler(t))
+		require.NoError(t, err)
+
+		childCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{
+			"hosts":  []interface{}{"a", "b"},
+			"labels": map[string]interface{}{"a": 1, "b": 2},
+		}), nil)
+		require.NoError(t, err)
+
+		merged := cp.Merge(parentCfg, childCfg).(*internalConfig).filterConfig.(*config)
+		assert.Equal(t, []string{"c", "a", "b"}, merged.Hosts)
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, merged.Labels)
+	})
+}
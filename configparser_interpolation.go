@@ -0,0 +1,112 @@
+package gonvoy
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const fileInterpolationPrefix = "file:"
+
+// interpolateStruct replaces the placeholders within every string value of the given struct, see interpolate.
+// It modifies the struct in place.
+func interpolateStruct(s *structpb.Struct) error {
+	for key, value := range s.GetFields() {
+		if err := interpolateValue(value); err != nil {
+			return fmt.Errorf("%s; %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func interpolateValue(v *structpb.Value) error {
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		s, err := interpolate(kind.StringValue)
+		if err != nil {
+			return err
+		}
+
+		kind.StringValue = s
+
+	case *structpb.Value_StructValue:
+		return interpolateStruct(kind.StructValue)
+
+	case *structpb.Value_ListValue:
+		for i, item := range kind.ListValue.GetValues() {
+			if err := interpolateValue(item); err != nil {
+				return fmt.Errorf("[%d]; %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// interpolate replaces the placeholders within the given string, with the following forms:
+//   - `${VAR}` is replaced with the value of the VAR environment variable, it fails if the variable is not set.
+//   - `${VAR:-fallback}` is replaced with the value of the VAR environment variable, or the fallback if the variable is not set or empty.
+//   - `${file:/path/to/file}` is replaced with the content of the file, without the trailing newline.
+//   - `$${...}` is an escaped placeholder, it is replaced with the literal `${...}`.
+func interpolate(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			sb.WriteString(s)
+			return sb.String(), nil
+		}
+
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in %q", s)
+		}
+		end += start
+
+		if start > 0 && s[start-1] == '$' {
+			// escaped placeholder, strip the escaping dollar sign and keep the rest as it is
+			sb.WriteString(s[:start-1])
+			sb.WriteString(s[start : end+1])
+			s = s[end+1:]
+			continue
+		}
+
+		value, err := resolvePlaceholder(s[start+2 : end])
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString(s[:start])
+		sb.WriteString(value)
+		s = s[end+1:]
+	}
+}
+
+func resolvePlaceholder(placeholder string) (string, error) {
+	if path, ok := strings.CutPrefix(placeholder, fileInterpolationPrefix); ok {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to read file placeholder; %w", err)
+		}
+
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+
+	name, fallback, hasFallback := strings.Cut(placeholder, ":-")
+	value, ok := os.LookupEnv(name)
+	switch {
+	case hasFallback && value == "":
+		return fallback, nil
+	case !ok:
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+
+	return value, nil
+}
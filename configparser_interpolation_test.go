@@ -0,0 +1,87 @@
+package gonvoy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("GONVOY_TEST_VAR", "value")
+	t.Setenv("GONVOY_TEST_EMPTY", "")
+
+	secret := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("s3cr3t\n"), 0o600))
+
+	testcases := []struct {
+		name     string
+		input    string
+		expected string
+		err      string
+	}{
+		{name: "no placeholder", input: "plain", expected: "plain"},
+		{name: "environment variable", input: "prefix-${GONVOY_TEST_VAR}-suffix", expected: "prefix-value-suffix"},
+		{name: "multiple placeholders", input: "${GONVOY_TEST_VAR}/${GONVOY_TEST_VAR}", expected: "value/value"},
+		{name: "fallback", input: "${GONVOY_TEST_UNSET:-fallback}", expected: "fallback"},
+		{name: "fallback on empty", input: "${GONVOY_TEST_EMPTY:-fallback}", expected: "fallback"},
+		{name: "empty variable", input: "${GONVOY_TEST_EMPTY}", expected: ""},
+		{name: "file", input: "Bearer ${file:" + secret + "}", expected: "Bearer s3cr3t"},
+		{name: "escaped", input: "$${GONVOY_TEST_VAR}", expected: "${GONVOY_TEST_VAR}"},
+		{name: "unset variable", input: "${GONVOY_TEST_UNSET}", err: "environment variable GONVOY_TEST_UNSET is not set"},
+		{name: "missing file", input: "${file:/does/not/exist}", err: "unable to read file placeholder"},
+		{name: "unterminated", input: "${GONVOY_TEST_VAR", err: "unterminated placeholder"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := interpolate(tc.input)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func TestConfigParser_Interpolation(t *testing.T) {
+	t.Setenv("GONVOY_TEST_VAR", "value")
+
+	configAny := newTestConfigAny(t, map[string]interface{}{
+		"a":      "${GONVOY_TEST_VAR}",
+		"nested": map[string]interface{}{"b": "${GONVOY_TEST_VAR}"},
+		"list":   []interface{}{"${GONVOY_TEST_VAR}", 1},
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{})
+		cfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"a": "${GONVOY_TEST_VAR}"}), newConfigCallbackHandler(t))
+		require.NoError(t, err)
+
+		filterCfg := cfg.(*internalConfig).filterConfig.(gjson.Result)
+		assert.Equal(t, "${GONVOY_TEST_VAR}", filterCfg.Get("a").Str)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{EnableConfigInterpolation: true})
+		cfg, err := cp.Parse(configAny, newConfigCallbackHandler(t))
+		require.NoError(t, err)
+
+		filterCfg := cfg.(*internalConfig).filterConfig.(gjson.Result)
+		assert.Equal(t, "value", filterCfg.Get("a").Str)
+		assert.Equal(t, "value", filterCfg.Get("nested.b").Str)
+		assert.Equal(t, "value", filterCfg.Get("list.0").Str)
+	})
+
+	t.Run("unresolved placeholder rejects the config", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{EnableConfigInterpolation: true})
+		_, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"a": "${GONVOY_TEST_UNSET}"}), newConfigCallbackHandler(t))
+		assert.ErrorContains(t, err, "configparser: interpolation failed; a; environment variable GONVOY_TEST_UNSET is not set")
+	})
+}
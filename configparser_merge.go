@@ -0,0 +1,313 @@
+package gonvoy
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+const mergeTagName = "envoy"
+
+// mergeStrategy represents the merge behavior of a filter config field, given by the `envoy:"..."` struct tag.
+//
+// The supported tag values are:
+//   - `mergeable`, the child value replaces the parent value, even when it is unset.
+//   - `replace`, an alias of `mergeable`.
+//   - `mergeable,preserve_root`, the child value replaces the parent value, unless it is unset.
+//   - `mergeable,append`, the child slice is appended to the parent slice, unless it is unset.
+//   - `mergeable,deep`, the child struct, pointer to struct, or map is recursively merged into the parent,
+//     where an unset child keeps the parent value.
+//
+// A child value is unset when its key is absent, or null, on the child filter config, see fieldPresence.
+// Hence, a child value explicitly set to its zero or `default:"..."` value still takes precedence over the parent value.
+//
+// During a deep merge, the nested struct fields follow their own `envoy` tags,
+// whereas the nested fields without the tag are replaced by the child value, unless it is unset.
+// To keep the parent value of a nested field, tag it without the `mergeable` option, e.g., `envoy:"-"`.
+// Likewise, the map entries of the child replace the parent entries, while the nested maps are recursively merged.
+// A field without the `envoy` tag on the root filter config is never merged, hence it always references the parent value.
+type mergeStrategy struct {
+	mergeable    bool
+	preserveRoot bool
+	append       bool
+	deep         bool
+}
+
+func parseMergeTag(tag string) mergeStrategy {
+	var s mergeStrategy
+	for _, opt := range strings.Split(tag, ",") {
+		switch strings.TrimSpace(opt) {
+		case "mergeable", "replace":
+			s.mergeable = true
+		case "preserve_root":
+			s.preserveRoot = true
+		case "append":
+			s.append = true
+		case "deep":
+			s.deep = true
+		}
+	}
+
+	return s
+}
+
+// fieldPresence records the keys given on a JSON object, along with the keys of its nested objects.
+// It tells apart a child field left unset from a child field explicitly set to its zero value,
+// as well as to its default value, since the defaults are applied to the child filter config prior to the merge.
+//
+// A nil fieldPresence means the keys are unknown, e.g., the child filter config isn't parsed from JSON,
+// hence a child field is considered unset when it is a zero value.
+type fieldPresence map[string]fieldPresence
+
+func newFieldPresence(result gjson.Result) fieldPresence {
+	if !result.IsObject() {
+		return nil
+	}
+
+	presence := fieldPresence{}
+	result.ForEach(func(key, value gjson.Result) bool {
+		// A null value leaves the field as it is on the unmarshalling, hence it is unset as well.
+		if value.Type != gjson.Null {
+			presence[key.String()] = newFieldPresence(value)
+		}
+
+		return true
+	})
+
+	return presence
+}
+
+// field reports whether the struct field is given, along with the keys of its nested object, if any.
+// Like the JSON unmarshalling, the key is matched case-insensitively, while an exact match is preferred.
+func (p fieldPresence) field(field reflect.StructField, v reflect.Value) (fieldPresence, bool) {
+	if p == nil {
+		return nil, !v.IsZero()
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return nil, false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		// The fields of an embedded struct are promoted to the enclosing JSON object.
+		if field.Anonymous {
+			return p, true
+		}
+
+		name = field.Name
+	}
+
+	if nested, ok := p[name]; ok {
+		return nested, true
+	}
+
+	for key, nested := range p {
+		if strings.EqualFold(key, name) {
+			return nested, true
+		}
+	}
+
+	return nil, false
+}
+
+// entry returns the keys of the nested object given on the map entry, if any.
+func (p fieldPresence) entry(key reflect.Value) fieldPresence {
+	if p == nil {
+		return nil
+	}
+
+	return p[fmt.Sprint(key.Interface())]
+}
+
+// mergeFields merges the child struct fields into the dst struct, which holds a copy of the parent struct.
+// The nested reports whether the struct is a nested struct within a deep merge,
+// and the presence records the keys given on the child struct, see fieldPresence.
+func mergeFields(dst, child reflect.Value, nested bool, presence fieldPresence) {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		var s mergeStrategy
+		tag, ok := field.Tag.Lookup(mergeTagName)
+		switch {
+		case ok:
+			s = parseMergeTag(tag)
+		case nested:
+			s = mergeStrategy{mergeable: true, preserveRoot: true}
+		}
+
+		if !s.mergeable {
+			continue
+		}
+
+		// A child field holds its default value when unset, hence it is told apart by the presence of its key,
+		// otherwise the default slice is appended twice, or the default map entries override the parent entries.
+		v := child.Field(i)
+		keys, ok := presence.field(field, v)
+		if (s.preserveRoot || s.append || s.deep) && !ok {
+			continue
+		}
+
+		dst.Field(i).Set(mergeValue(dst.Field(i), v, s, keys))
+	}
+}
+
+// mergeValue returns the merged value of the parent and child values based on the merge strategy.
+// It never modifies the parent value, as the parent config is shared with the other routes.
+func mergeValue(parent, child reflect.Value, s mergeStrategy, presence fieldPresence) reflect.Value {
+	switch {
+	case s.append && child.Kind() == reflect.Slice:
+		if child.Len() == 0 {
+			return parent
+		}
+
+		merged := reflect.MakeSlice(parent.Type(), 0, parent.Len()+child.Len())
+		merged = reflect.AppendSlice(merged, parent)
+		return reflect.AppendSlice(merged, child)
+
+	case s.deep:
+		return deepMergeValue(parent, child, presence)
+	}
+
+	return child
+}
+
+func deepMergeValue(parent, child reflect.Value, presence fieldPresence) reflect.Value {
+	switch child.Kind() {
+	case reflect.Struct:
+		if len(presence) == 0 && child.IsZero() {
+			return parent
+		}
+
+		merged := reflect.New(parent.Type()).Elem()
+		merged.Set(parent)
+		mergeFields(merged, child, true, presence)
+		return merged
+
+	case reflect.Pointer:
+		switch {
+		case child.IsNil():
+			return parent
+		case parent.IsNil() || child.Elem().Kind() != reflect.Struct:
+			return child
+		}
+
+		merged := reflect.New(parent.Type().Elem())
+		merged.Elem().Set(parent.Elem())
+		mergeFields(merged.Elem(), child.Elem(), true, presence)
+		return merged
+
+	case reflect.Map:
+		switch {
+		case child.Len() == 0:
+			return parent
+		case parent.Len() == 0:
+			return child
+		}
+
+		merged := reflect.MakeMapWithSize(parent.Type(), parent.Len()+child.Len())
+		iter := parent.MapRange()
+		for iter.Next() {
+			merged.SetMapIndex(iter.Key(), iter.Value())
+		}
+
+		iter = child.MapRange()
+		for iter.Next() {
+			value := iter.Value()
+			if existing := merged.MapIndex(iter.Key()); existing.IsValid() && isDeepMergeable(existing, value) {
+				value = deepMergeValue(existing, value, presence.entry(iter.Key()))
+			}
+
+			merged.SetMapIndex(iter.Key(), value)
+		}
+
+		return merged
+
+	case reflect.Interface:
+		if child.IsNil() {
+			return parent
+		}
+
+		if parent.IsNil() || !isDeepMergeable(parent.Elem(), child.Elem()) {
+			return child
+		}
+
+		merged := reflect.New(parent.Type()).Elem()
+		merged.Set(deepMergeValue(parent.Elem(), child.Elem(), presence))
+		return merged
+	}
+
+	return child
+}
+
+// isDeepMergeable reports whether both values are maps, structs, or pointer to structs of the same type.
+func isDeepMergeable(parent, child reflect.Value) bool {
+	if parent.Kind() == reflect.Interface && !parent.IsNil() {
+		parent = parent.Elem()
+	}
+
+	if child.Kind() == reflect.Interface && !child.IsNil() {
+		child = child.Elem()
+	}
+
+	if !parent.IsValid() || !child.IsValid() || parent.Type() != child.Type() {
+		return false
+	}
+
+	switch child.Kind() {
+	case reflect.Map, reflect.Struct:
+		return true
+	case reflect.Pointer:
+		return child.Type().Elem().Kind() == reflect.Struct
+	}
+
+	return false
+}
+
+// mergeJSONObject merges the child JSON object into the parent JSON object, in which the child values take precedence.
+// The parent keys absent on the child are kept, whereas the other values, including the nested objects, are replaced by the child values,
+// unless a merge strategy is given for the path of the key, see ConfigOptions.MergeStrategies.
+func mergeJSONObject(parent, child map[string]interface{}, strategies map[string]mergeStrategy, path string) map[string]interface{} {
+	merged := make(map[string]interface{}, len(parent)+len(child))
+	for k, v := range parent {
+		merged[k] = v
+	}
+
+	for k, v := range child {
+		keyPath := k
+		if path != "" {
+			keyPath = path + "." + k
+		}
+
+		s := strategies[keyPath]
+		if v == nil && s.preserveRoot {
+			continue
+		}
+
+		switch parentValue := merged[k].(type) {
+		case map[string]interface{}:
+			if childObject, ok := v.(map[string]interface{}); ok && s.deep {
+				merged[k] = mergeJSONObject(parentValue, childObject, strategies, keyPath)
+				continue
+			}
+
+		case []interface{}:
+			if childArray, ok := v.([]interface{}); ok && s.append {
+				merged[k] = append(slices.Clone(parentValue), childArray...)
+				continue
+			}
+		}
+
+		merged[k] = v
+	}
+
+	return merged
+}
@@ -0,0 +1,153 @@
+package gonvoy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type mergeNested struct {
+	Timeout  int               `json:"timeout"`
+	Enabled  bool              `json:"enabled"`
+	Name     string            `json:"name" envoy:"mergeable"`
+	Fixed    string            `json:"fixed" envoy:"-"`
+	Labels   map[string]string `json:"labels" envoy:"mergeable,deep"`
+	Disabled []string          `json:"disabled" envoy:"mergeable,append"`
+}
+
+type mergeConfig struct {
+	Nested        mergeNested            `json:"nested" envoy:"mergeable,deep"`
+	NestedPointer *mergeNested           `json:"nestedPointer" envoy:"mergeable,deep"`
+	Headers       map[string]string      `json:"headers" envoy:"mergeable,deep"`
+	Any           map[string]interface{} `json:"any" envoy:"mergeable,deep"`
+	Hosts         []string               `json:"hosts" envoy:"mergeable,append"`
+	Replaced      []string               `json:"replaced" envoy:"replace"`
+	Untouched     string                 `json:"untouched"`
+}
+
+func TestConfigParser_mergeStrategies(t *testing.T) {
+	parent := &mergeConfig{
+		Nested: mergeNested{
+			Timeout:  10,
+			Name:     "parent",
+			Fixed:    "parent",
+			Labels:   map[string]string{"team": "core", "tier": "1"},
+			Disabled: []string{"a"},
+		},
+		NestedPointer: &mergeNested{Timeout: 10, Name: "parent"},
+		Headers:       map[string]string{"x-parent": "1", "x-shared": "parent"},
+		Any: map[string]interface{}{
+			"limits": map[string]interface{}{"rps": 10, "burst": 20},
+		},
+		Hosts:     []string{"a.com"},
+		Replaced:  []string{"parent"},
+		Untouched: "parent",
+	}
+
+	child := &mergeConfig{
+		Nested: mergeNested{
+			Enabled:  true,
+			Fixed:    "child",
+			Labels:   map[string]string{"tier": "2"},
+			Disabled: []string{"b"},
+		},
+		NestedPointer: &mergeNested{Timeout: 30},
+		Headers:       map[string]string{"x-shared": "child"},
+		Any: map[string]interface{}{
+			"limits": map[string]interface{}{"rps": 100},
+		},
+		Hosts:     []string{"b.com"},
+		Untouched: "child",
+	}
+
+	cp := &configParser{}
+	merged, err := cp.mergeStruct(parent, child, nil)
+	require.NoError(t, err)
+
+	m := merged.(*mergeConfig)
+	assert.Equal(t, mergeNested{
+		Timeout:  10,
+		Enabled:  true,
+		Name:     "",
+		Fixed:    "parent",
+		Labels:   map[string]string{"team": "core", "tier": "2"},
+		Disabled: []string{"a", "b"},
+	}, m.Nested)
+	assert.Equal(t, &mergeNested{Timeout: 30, Name: ""}, m.NestedPointer)
+	assert.Equal(t, map[string]string{"x-parent": "1", "x-shared": "child"}, m.Headers)
+	assert.Equal(t, map[string]interface{}{
+		"limits": map[string]interface{}{"rps": 100, "burst": 20},
+	}, m.Any)
+	assert.Equal(t, []string{"a.com", "b.com"}, m.Hosts)
+	assert.Nil(t, m.Replaced)
+	assert.Equal(t, "parent", m.Untouched)
+
+	// the parent config must be left intact
+	assert.Equal(t, map[string]string{"team": "core", "tier": "1"}, parent.Nested.Labels)
+	assert.Equal(t, &mergeNested{Timeout: 10, Name: "parent"}, parent.NestedPointer)
+	assert.Equal(t, map[string]string{"x-parent": "1", "x-shared": "parent"}, parent.Headers)
+	assert.Equal(t, map[string]interface{}{"rps": 10, "burst": 20}, parent.Any["limits"])
+	assert.Equal(t, []string{"a.com"}, parent.Hosts)
+
+	t.Run("unset child keeps the parent on deep merge", func(t *testing.T) {
+		merged, err := cp.mergeStruct(parent, &mergeConfig{}, nil)
+		require.NoError(t, err)
+
+		m := merged.(*mergeConfig)
+		assert.Equal(t, parent.Nested, m.Nested)
+		assert.Equal(t, parent.NestedPointer, m.NestedPointer)
+		assert.Equal(t, parent.Headers, m.Headers)
+		assert.Equal(t, parent.Hosts, m.Hosts)
+	})
+
+	t.Run("child values given explicitly are merged even when zero", func(t *testing.T) {
+		parent := &mergeConfig{
+			Nested:    mergeNested{Timeout: 10, Enabled: true, Name: "parent"},
+			Headers:   map[string]string{"x-parent": "1"},
+			Untouched: "parent",
+		}
+
+		keys := newFieldPresence(gjson.Parse(`{"nested":{"timeout":0,"enabled":false},"untouched":"child"}`))
+		merged, err := cp.mergeStruct(parent, &mergeConfig{Untouched: "child"}, keys)
+		require.NoError(t, err)
+
+		m := merged.(*mergeConfig)
+		assert.Equal(t, mergeNested{Timeout: 0, Enabled: false, Name: ""}, m.Nested)
+		assert.Equal(t, parent.Headers, m.Headers, "absent child keeps the parent")
+		assert.Equal(t, "parent", m.Untouched)
+	})
+}
+
+func TestConfigParser_mergeLiteral(t *testing.T) {
+	parent := gjson.Parse(`{"a":"parent","nested":{"x":1,"y":{"z":1}},"list":[1,2],"kept":"parent"}`)
+	child := gjson.Parse(`{"b":"child","nested":{"y":{"w":2}},"list":[3],"kept":null}`)
+
+	t.Run("child values replace the parent values by default", func(t *testing.T) {
+		cp := &configParser{}
+		merged := cp.mergeLiteral(parent, child)
+
+		assert.JSONEq(t, `{"a":"parent","b":"child","nested":{"y":{"w":2}},"list":[3],"kept":null}`, merged.Raw)
+	})
+
+	t.Run("child values are merged following the merge strategies", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{MergeStrategies: map[string]string{
+			"nested":   "mergeable,deep",
+			"nested.y": "mergeable,deep",
+			"list":     "mergeable,append",
+			"kept":     "mergeable,preserve_root",
+		}}).(*configParser)
+		merged := cp.mergeLiteral(parent, child)
+
+		assert.JSONEq(t, `{"a":"parent","b":"child","nested":{"x":1,"y":{"z":1,"w":2}},"list":[1,2,3],"kept":"parent"}`, merged.Raw)
+		assert.JSONEq(t, `[1,2]`, parent.Get("list").Raw, "the parent config must be left intact")
+	})
+
+	t.Run("nested objects without a merge strategy are replaced on deep merge", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{MergeStrategies: map[string]string{"nested": "mergeable,deep"}}).(*configParser)
+		merged := cp.mergeLiteral(parent, child)
+
+		assert.JSONEq(t, `{"a":"parent","b":"child","nested":{"x":1,"y":{"w":2}},"list":[3],"kept":null}`, merged.Raw)
+	})
+}
@@ -1,11 +1,14 @@
 package gonvoy
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
 	xds "github.com/cncf/xds/go/xds/type/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/tidwall/gjson"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -29,8 +32,27 @@ type dummyConfig struct {
 	any interface{}
 }
 
+func newTestConfigAny(t *testing.T, value map[string]interface{}) *anypb.Any {
+	v, err := structpb.NewStruct(value)
+	require.NoError(t, err)
+
+	a, err := anypb.New(&xds.TypedStruct{Value: v})
+	require.NoError(t, err)
+	return a
+}
+
+// newConfigCallbackHandler returns the root config callbacks, which the cache counters are defined against once the root config is parsed.
+func newConfigCallbackHandler(t *testing.T) *mock_envoy.ConfigCallbackHandler {
+	cc := mock_envoy.NewConfigCallbackHandler(t)
+	cc.EXPECT().DefineCounterMetric(mock.MatchedBy(func(name string) bool {
+		return strings.HasPrefix(name, "cache_")
+	})).Return(mock_envoy.NewCounterMetric(t)).Maybe()
+
+	return cc
+}
+
 func TestConfigParser(t *testing.T) {
-	mockCC := mock_envoy.NewConfigCallbackHandler(t)
+	mockCC := newConfigCallbackHandler(t)
 
 	parentValue, err := structpb.NewStruct(map[string]interface{}{
 		"a":      "parent value",
@@ -163,7 +185,8 @@ func TestConfigParser(t *testing.T) {
 		assert.Same(t, parentCfg.(*internalConfig).callbacks, mergedInternalConfig.callbacks)
 		assert.Same(t, mergedInternalConfig.callbacks, childCfg.(*internalConfig).callbacks)
 		assert.NotSame(t, parentCfg.(*internalConfig).filterConfig, mergedInternalConfig.filterConfig)
-		assert.Same(t, mergedInternalConfig.filterConfig, childCfg.(*internalConfig).filterConfig)
+		assert.NotSame(t, mergedInternalConfig.filterConfig, childCfg.(*internalConfig).filterConfig)
+		assert.Equal(t, "child value", childCfg.(*internalConfig).filterConfig.(*dummyConfig).A, "the child config must be left intact")
 	})
 
 	t.Run("with filter config | Always use Child config", func(t *testing.T) {
@@ -240,6 +263,31 @@ func TestConfigParser(t *testing.T) {
 		assert.Nil(t, pMergedCfg.S)
 	})
 
+	t.Run("metric registry is shared and reset once the root config is reloaded", func(t *testing.T) {
+		cc := newConfigCallbackHandler(t)
+		cc.EXPECT().DefineCounterMetric("foo_").Return(mock_envoy.NewCounterMetric(t)).Once()
+
+		cp := NewConfigParser(ConfigOptions{})
+		parentCfg, err := cp.Parse(parentConfigAny, cc)
+		require.NoError(t, err)
+		childCfg, err := cp.Parse(childConfigAny, nil)
+		require.NoError(t, err)
+
+		registry := parentCfg.(*internalConfig).metrics
+		assert.Same(t, registry, childCfg.(*internalConfig).metrics)
+
+		registry.Counter("foo")
+		assert.Contains(t, registry.counterMap, "foo_")
+
+		_, err = cp.Parse(parentConfigAny, cc)
+		require.NoError(t, err)
+		assert.Contains(t, registry.counterMap, "foo_", "same config callbacks must keep the metric handles")
+
+		_, err = cp.Parse(parentConfigAny, newConfigCallbackHandler(t))
+		require.NoError(t, err)
+		assert.NotContains(t, registry.counterMap, "foo_")
+		assert.Contains(t, registry.counterMap, "cache_lookups_total_result=hit", "cache counters must be resolved against the renewed config callbacks")
+	})
 }
 
 func TestConfigParser_mergeStruct(t *testing.T) {
@@ -263,7 +311,7 @@ func TestConfigParser_mergeStruct(t *testing.T) {
 
 	configParser := &configParser{}
 
-	merged, err := configParser.mergeStruct(parent, child)
+	merged, err := configParser.mergeStruct(parent, child, nil)
 	assert.NoError(t, err)
 
 	mergedConfig, ok := merged.(*dummyConfig)
@@ -286,7 +334,7 @@ func TestConfigParser_mergeStruct(t *testing.T) {
 		any: "i make it wrong",
 	}
 
-	merged2, err := configParser.mergeStruct(parent, child2)
+	merged2, err := configParser.mergeStruct(parent, child2, nil)
 	assert.NoError(t, err)
 
 	mergedConfig2, ok := merged2.(*dummyConfig)
@@ -298,3 +346,82 @@ func TestConfigParser_mergeStruct(t *testing.T) {
 	assert.NotSame(t, parent.S, mergedConfig2.S)
 	assert.NotSame(t, mergedConfig.S, mergedConfig2.S)
 }
+
+type validatedConfig struct {
+	A string `json:"a"`
+	B int    `json:"b" envoy:"mergeable"`
+}
+
+func (c *validatedConfig) Validate() error {
+	if c.A == "" {
+		return errors.New("a is required")
+	}
+
+	if c.B < 0 {
+		return errors.New("b must be positive")
+	}
+
+	return nil
+}
+
+func TestConfigParser_Validation(t *testing.T) {
+	mockCC := newConfigCallbackHandler(t)
+
+	t.Run("invalid root config is rejected during parse", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(validatedConfig)})
+
+		_, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"b": 1}), mockCC)
+		assert.ErrorContains(t, err, "configparser: validation failed; a is required")
+	})
+
+	t.Run("omitted root config is built from its defaults and validated during parse", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(validatedConfig)})
+
+		_, err := cp.Parse(&anypb.Any{}, mockCC)
+		assert.ErrorContains(t, err, "configparser: validation failed; a is required")
+	})
+
+	t.Run("partial child config is validated once merged", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(validatedConfig)})
+
+		parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"a": "parent"}), mockCC)
+		require.NoError(t, err)
+		childCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"b": 1}), nil)
+		require.NoError(t, err)
+
+		merged := cp.Merge(parentCfg, childCfg).(*internalConfig)
+		assert.NoError(t, merged.configErr)
+		assert.NoError(t, applyInternalConfig(&context{}, merged))
+	})
+
+	t.Run("invalid merged config is kept and returned on the request path", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(validatedConfig)})
+
+		parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"a": "parent"}), mockCC)
+		require.NoError(t, err)
+		childCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"b": -1}), nil)
+		require.NoError(t, err)
+
+		merged := cp.Merge(parentCfg, childCfg).(*internalConfig)
+		assert.ErrorContains(t, merged.configErr, "b must be positive")
+		assert.ErrorContains(t, applyInternalConfig(&context{}, merged), "b must be positive")
+	})
+
+	t.Run("invalid merged config fallbacks to the parent config when merge error is ignored", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(validatedConfig), IgnoreMergeError: true})
+
+		parentCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"a": "parent"}), mockCC)
+		require.NoError(t, err)
+		childCfg, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"b": -1}), nil)
+		require.NoError(t, err)
+
+		assert.Same(t, parentCfg, cp.Merge(parentCfg, childCfg))
+	})
+
+	t.Run("child config is validated during parse when it is always used", func(t *testing.T) {
+		cp := NewConfigParser(ConfigOptions{FilterConfig: new(validatedConfig), AlwaysUseChildConfig: true})
+
+		_, err := cp.Parse(newTestConfigAny(t, map[string]interface{}{"b": 1}), nil)
+		assert.ErrorContains(t, err, "configparser: validation failed; a is required")
+	})
+}
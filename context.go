@@ -6,6 +6,8 @@ import (
 
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
 	"github.com/go-logr/logr"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/protobuf/proto"
 )
 
 // HttpFilterContext represents the context object for an HTTP filter.
@@ -41,6 +43,22 @@ type HttpFilterContext interface {
 	//
 	ResponseBody() Body
 
+	// RequestTrailer provides an interface to access and modify HTTP Request trailer, including
+	// add, overwrite, or delete existing trailer.
+	//
+	// A panic is returned when the HTTP request carries no trailers, or RequestTrailer accessed outside from the following phases:
+	// OnRequestTrailer
+	//
+	RequestTrailer() Header
+
+	// ResponseTrailer provides an interface to access and modify HTTP Response trailer, including
+	// add, overwrite, or delete existing trailer.
+	//
+	// A panic is returned when the HTTP response carries no trailers, or ResponseTrailer accessed outside from the following phases:
+	// OnResponseTrailer
+	//
+	ResponseTrailer() Header
+
 	// Request returns an http.Request struct, which is a read-only data.
 	// Any attempts to alter this value will not affect to the actual request.
 	// For any modifications, please use RequestHeader or RequestBody.
@@ -63,6 +81,11 @@ type HttpFilterContext interface {
 	//
 	Response() *http.Response
 
+	// PathParam returns the value of the path parameter from the route pattern matched by the request, see HttpFilterController.Route.
+	// The value is path-unescaped. It returns an empty string if the parameter doesn't exist or no route has been matched.
+	//
+	PathParam(name string) string
+
 	// SetRequestHost modifies the host of the request.
 	// This can be used to dynamically change the request host based on specific conditions for routing.
 	// However, to re-evaluate routing decisions, the filter must explicitly trigger ReloadRoute,
@@ -100,6 +123,14 @@ type HttpFilterContext interface {
 	//
 	LoadResponseBody(buffer api.BufferInstance, endStream bool)
 
+	// LoadRequestTrailers is a low-level API, it loads HTTP request trailers from Envoy during DecodeTrailers phase
+	//
+	LoadRequestTrailers(api.RequestTrailerMap)
+
+	// LoadResponseTrailers is a low-level API, it loads HTTP response trailers from Envoy during EncodeTrailers phase
+	//
+	LoadResponseTrailers(api.ResponseTrailerMap)
+
 	// IsRequestBodyAccessible checks if the request body is accessible for reading or writing.
 	//
 	IsRequestBodyAccessible() bool
@@ -124,6 +155,14 @@ type HttpFilterContext interface {
 	//
 	IsResponseBodyWritable() bool
 
+	// IsRequestBodyStreaming specifies whether an HTTP Request body is processed chunk by chunk, see EnableRequestBodyStreaming option.
+	//
+	IsRequestBodyStreaming() bool
+
+	// IsResponseBodyStreaming specifies whether an HTTP Response body is processed chunk by chunk, see EnableResponseBodyStreaming option.
+	//
+	IsResponseBodyStreaming() bool
+
 	// SendResponse dispatches a response with a specified status code, body, and optional localreply options.
 	// Use the JSON() method when you need to respond with a JSON content-type.
 	// For plain text responses, use the String() method.
@@ -142,6 +181,23 @@ type HttpFilterContext interface {
 	// This action halts the handler chaining and immediately returns back to Envoy.
 	String(code int, s string, opts ...LocalReplyOption) error
 
+	// ErrorRenderer returns the renderer of the error responses, see ConfigOptions.ErrorRenderer.
+	// It is intended for a custom ErrorHandler to render the error responses consistently.
+	//
+	ErrorRenderer() ErrorRenderer
+
+	// Negotiate dispatches a response with a status code, where the value is rendered with the renderer negotiated from the request Accept header,
+	// see ConfigOptions.Renderers and ConfigOptions.NegotiationFallback. The response carries the `Vary: Accept` header.
+	//
+	// This action halts the handler chaining and immediately returns back to Envoy.
+	Negotiate(code int, value interface{}, opts ...LocalReplyOption) error
+
+	// GRPCError dispatches a gRPC error response with a gRPC status code, filling the grpc-message and,
+	// when the details are given, the grpc-status-details-bin headers, see google.rpc.Status.
+	//
+	// This action halts the handler chaining and immediately returns back to Envoy.
+	GRPCError(grpcCode code.Code, message string, details ...proto.Message) error
+
 	// SkipNextPhase immediately returns to the Envoy without further progressing to the next handler.
 	// This action also enables users to bypass the next phase.
 	// In HTTP request flows, invoking it from OnRequestHeader skips OnRequestBody phase.
@@ -158,20 +214,10 @@ type HttpFilterContext interface {
 	ReloadRoute()
 }
 
-// RuntimeContext represents the runtime context for the filter in Envoy.
-// It provides various methods to interact with the Envoy proxy and retrieve information about the HTTP traffic.
-type RuntimeContext interface {
-	// GetProperty is a helper function to fetch Envoy attributes based on https://www.envoyproxy.io/docs/envoy/latest/intro/arch_overview/advanced/attributes.
-	// Currently, it only supports value that has a string-like format, work in progress for List/Map format.
-	//
-	GetProperty(name, defaultVal string) (string, error)
-
-	// StreamInfo offers an interface for retrieving comprehensive details about the incoming HTTP traffic, including
-	// information such as the route name, filter chain name, dynamic metadata, and more.
-	// It provides direct access to low-level Envoy information, so it's important to use it with a clear understanding of your intent.
-	//
-	StreamInfo() api.StreamInfo
-
+// ConfigContext represents the context of a filter configuration, which outlives the requests.
+// It is given to the filter configuration lifecycle hooks, see ConfigInitializer and ConfigDestroyer,
+// as well as to every request through RuntimeContext.
+type ConfigContext interface {
 	// GetFilterConfig returns the filter configuration associated with the route.
 	// It defaults to the parent filter configuration if no route filter configuration was found.
 	// Otherwise, once typed_per_filter_config present in the route then it will return the child filter configuration.
@@ -195,6 +241,39 @@ type RuntimeContext interface {
 	Metrics() Metrics
 }
 
+// RuntimeContext represents the runtime context for the filter in Envoy.
+// It provides various methods to interact with the Envoy proxy and retrieve information about the HTTP traffic.
+type RuntimeContext interface {
+	ConfigContext
+
+	// GetProperty is a helper function to fetch Envoy attributes based on https://www.envoyproxy.io/docs/envoy/latest/intro/arch_overview/advanced/attributes.
+	// It only supports value that has a string-like format, use Attributes for the typed access.
+	//
+	GetProperty(name, defaultVal string) (string, error)
+
+	// Attributes provides typed access to the Envoy attributes, such as integers, booleans, durations, timestamps, addresses, and header maps,
+	// as well as the well-known attributes, such as the route name, the cluster name, and the mTLS details.
+	//
+	Attributes() *Attributes
+
+	// StreamInfo offers an interface for retrieving comprehensive details about the incoming HTTP traffic, including
+	// information such as the route name, filter chain name, dynamic metadata, and more.
+	// It provides direct access to low-level Envoy information, so it's important to use it with a clear understanding of your intent.
+	//
+	StreamInfo() api.StreamInfo
+
+	// DynamicMetadata provides an interface to set and get the dynamic metadata of the current request,
+	// which is visible to the subsequent Envoy filters (e.g., ext_authz, RBAC) and to the access logs.
+	// The metadata is stored under the filter name namespace, see ConfigOptions.FilterName, use DynamicMetadata.Namespace to select another namespace.
+	//
+	DynamicMetadata() DynamicMetadata
+
+	// FilterState provides an interface to set and get the filter state of the current request,
+	// which is visible to the subsequent Envoy filters, the access log formatters, and optionally to the upstream connections.
+	//
+	FilterState() FilterState
+}
+
 // Context represents the interface for a context within the filter.
 // It extends the RuntimeContext and HttpFilterContext interfaces.
 type Context interface {
@@ -238,12 +317,17 @@ type context struct {
 
 	reqHeaderMap       api.RequestHeaderMap
 	respHeaderMap      api.ResponseHeaderMap
+	reqTrailerMap      api.RequestTrailerMap
+	respTrailerMap     api.ResponseTrailerMap
 	reqBufferInstance  api.BufferInstance
 	respBufferInstance api.BufferInstance
 	reqBufferBytes     []byte
 	respBufferBytes    []byte
 
 	autoReloadRoute bool
+	errorRenderer   ErrorRenderer
+
+	contentNegotiation *contentNegotiation
 
 	strictBodyAccess                bool
 	requestBodyAccessRead           bool
@@ -252,11 +336,18 @@ type context struct {
 	responseBodyAccessWrite         bool
 	preserveContentLengthOnRequest  bool
 	preserveContentLengthOnResponse bool
+	requestBodyStreaming            bool
+	responseBodyStreaming           bool
+
+	httpReq    *http.Request
+	httpResp   *http.Response
+	pathParams map[string]string
 
-	httpReq  *http.Request
-	httpResp *http.Response
+	attributes *Attributes
 
+	filterName   string
 	filterConfig interface{}
+	configScope  *configScope
 	cache        Cache
 	metrics      Metrics
 	logger       logr.Logger
@@ -0,0 +1,279 @@
+package gonvoy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+// List of well-known Envoy attributes, see https://www.envoyproxy.io/docs/envoy/latest/intro/arch_overview/advanced/attributes.
+const (
+	AttributeRequestID             = "request.id"
+	AttributeRequestTime           = "request.time"
+	AttributeRequestDuration       = "request.duration"
+	AttributeRequestHeaders        = "request.headers"
+	AttributeResponseCode          = "response.code"
+	AttributeResponseCodeDetails   = "response.code_details"
+	AttributeResponseHeaders       = "response.headers"
+	AttributeResponseTrailers      = "response.trailers"
+	AttributeSourceAddress         = "source.address"
+	AttributeDestinationAddress    = "destination.address"
+	AttributeUpstreamAddress       = "upstream.address"
+	AttributeConnectionMTLS        = "connection.mtls"
+	AttributeConnectionTLSVersion  = "connection.tls_version"
+	AttributeConnectionPeerSubject = "connection.subject_peer_certificate"
+	AttributeConnectionPeerURISAN  = "connection.uri_san_peer_certificate"
+	AttributeConnectionPeerDNSSAN  = "connection.dns_san_peer_certificate"
+	AttributeXDSRouteName          = "xds.route_name"
+	AttributeXDSClusterName        = "xds.cluster_name"
+	AttributeXDSFilterChainName    = "xds.filter_chain_name"
+)
+
+// Attributes provides typed access to the Envoy attributes of the current request,
+// see https://www.envoyproxy.io/docs/envoy/latest/intro/arch_overview/advanced/attributes.
+//
+// The typed getters return an error wrapping api.ErrValueNotFound when the attribute is not available,
+// e.g., the response attributes during the request phases.
+// Well-known attributes are available through the dedicated methods, e.g., RouteName, which are evaluated lazily on access,
+// and return a zero value when the attribute is not available.
+type Attributes struct {
+	c *context
+
+	// connValues holds the found connection attributes, which never change during the request,
+	// as an attribute lookup crosses the Go and C boundary.
+	connValues map[string]string
+}
+
+func (c *context) Attributes() *Attributes {
+	if c.attributes == nil {
+		c.attributes = &Attributes{c: c, connValues: make(map[string]string)}
+	}
+
+	return c.attributes
+}
+
+// GetString returns the attribute value as a string.
+func (a *Attributes) GetString(name string) (string, error) {
+	value, err := a.c.cb.GetProperty(name)
+	if err != nil {
+		if errors.Is(err, api.ErrSerializationFailure) {
+			return "", fmt.Errorf("attribute %s is not a string-like value, %w", name, err)
+		}
+
+		return "", fmt.Errorf("failed to get attribute %s, %w", name, err)
+	}
+
+	return value, nil
+}
+
+// GetInt returns the attribute value as an integer, e.g., `response.code`, `source.port`, or `request.size`.
+func (a *Attributes) GetInt(name string) (int64, error) {
+	value, err := a.GetString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse attribute %s as an integer, %w", name, err)
+	}
+
+	return n, nil
+}
+
+// GetBool returns the attribute value as a boolean, e.g., `connection.mtls`.
+func (a *Attributes) GetBool(name string) (bool, error) {
+	value, err := a.GetString(name)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse attribute %s as a boolean, %w", name, err)
+	}
+
+	return b, nil
+}
+
+// GetDuration returns the attribute value as a duration, e.g., `request.duration`.
+func (a *Attributes) GetDuration(name string) (time.Duration, error) {
+	value, err := a.GetString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse attribute %s as a duration, %w", name, err)
+	}
+
+	return d, nil
+}
+
+// GetTime returns the attribute value as a timestamp, e.g., `request.time`.
+func (a *Attributes) GetTime(name string) (time.Time, error) {
+	value, err := a.GetString(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse attribute %s as a timestamp, %w", name, err)
+	}
+
+	return t, nil
+}
+
+// GetAddress returns the attribute value as an IP address and a port, e.g., `source.address`, or `upstream.address`.
+// The port is zero when the address carries no port, e.g., a pipe address.
+func (a *Attributes) GetAddress(name string) (net.IP, int, error) {
+	value, err := a.GetString(name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	host, port := value, 0
+	if h, p, err := net.SplitHostPort(value); err == nil {
+		host = h
+		if port, err = strconv.Atoi(p); err != nil {
+			return nil, 0, fmt.Errorf("failed to parse attribute %s as an address, %w", name, err)
+		}
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, 0, fmt.Errorf("failed to parse attribute %s as an address, invalid IP %q", name, host)
+	}
+
+	return ip, port, nil
+}
+
+// GetList returns the attribute value as a list of strings.
+// Since Envoy doesn't serialize list attributes to the Go plugin yet, a string-like attribute is returned as a single item list,
+// e.g., `connection.uri_san_peer_certificate` which only holds the first URI SAN of the peer certificate.
+func (a *Attributes) GetList(name string) ([]string, error) {
+	value, err := a.GetString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{value}, nil
+}
+
+// GetMap returns the attribute value as a map of strings, with lowercase keys.
+// Since Envoy doesn't serialize map attributes to the Go plugin yet,
+// only `request.headers`, `response.headers`, and `response.trailers` are supported, which are read from the loaded header maps.
+func (a *Attributes) GetMap(name string) (map[string][]string, error) {
+	var headerMap api.HeaderMap
+	switch name {
+	case AttributeRequestHeaders:
+		headerMap = a.c.reqHeaderMap
+	case AttributeResponseHeaders:
+		headerMap = a.c.respHeaderMap
+	case AttributeResponseTrailers:
+		headerMap = a.c.respTrailerMap
+	default:
+		return nil, fmt.Errorf("attribute %s is not a supported map, %w", name, api.ErrSerializationFailure)
+	}
+
+	if headerMap == nil {
+		return nil, fmt.Errorf("attribute %s has not been set up yet, %w", name, api.ErrValueNotFound)
+	}
+
+	m := make(map[string][]string)
+	headerMap.Range(func(key, value string) bool {
+		m[key] = append(m[key], value)
+		return true
+	})
+
+	return m, nil
+}
+
+// RequestID returns the `request.id` attribute.
+func (a *Attributes) RequestID() string {
+	return a.stringOrZero(AttributeRequestID)
+}
+
+// RouteName returns the `xds.route_name` attribute.
+func (a *Attributes) RouteName() string {
+	return a.stringOrZero(AttributeXDSRouteName)
+}
+
+// ClusterName returns the `xds.cluster_name` attribute.
+func (a *Attributes) ClusterName() string {
+	return a.stringOrZero(AttributeXDSClusterName)
+}
+
+// ResponseCode returns the `response.code` attribute, which is only available during the response phases.
+func (a *Attributes) ResponseCode() int {
+	code, _ := a.GetInt(AttributeResponseCode)
+	return int(code)
+}
+
+// ResponseCodeDetails returns the `response.code_details` attribute, which is only available during the response phases.
+func (a *Attributes) ResponseCodeDetails() string {
+	return a.stringOrZero(AttributeResponseCodeDetails)
+}
+
+// SourceAddress returns the `source.address` attribute, which is the downstream connection remote address.
+func (a *Attributes) SourceAddress() (net.IP, int) {
+	ip, port, _ := a.GetAddress(AttributeSourceAddress)
+	return ip, port
+}
+
+// DestinationAddress returns the `destination.address` attribute, which is the downstream connection local address.
+func (a *Attributes) DestinationAddress() (net.IP, int) {
+	ip, port, _ := a.GetAddress(AttributeDestinationAddress)
+	return ip, port
+}
+
+// MTLS reports whether the downstream connection is using mutual TLS, see `connection.mtls` attribute.
+func (a *Attributes) MTLS() bool {
+	mtls, _ := strconv.ParseBool(a.connStringOrZero(AttributeConnectionMTLS))
+	return mtls
+}
+
+// TLSVersion returns the `connection.tls_version` attribute, e.g., TLSv1.3.
+func (a *Attributes) TLSVersion() string {
+	return a.connStringOrZero(AttributeConnectionTLSVersion)
+}
+
+// PeerSubject returns the `connection.subject_peer_certificate` attribute.
+func (a *Attributes) PeerSubject() string {
+	return a.connStringOrZero(AttributeConnectionPeerSubject)
+}
+
+// PeerURISAN returns the `connection.uri_san_peer_certificate` attribute, e.g., a SPIFFE ID.
+func (a *Attributes) PeerURISAN() string {
+	return a.connStringOrZero(AttributeConnectionPeerURISAN)
+}
+
+// PeerDNSSAN returns the `connection.dns_san_peer_certificate` attribute.
+func (a *Attributes) PeerDNSSAN() string {
+	return a.connStringOrZero(AttributeConnectionPeerDNSSAN)
+}
+
+func (a *Attributes) stringOrZero(name string) string {
+	value, _ := a.GetString(name)
+	return value
+}
+
+func (a *Attributes) connStringOrZero(name string) string {
+	if value, ok := a.connValues[name]; ok {
+		return value
+	}
+
+	value, err := a.GetString(name)
+	if err != nil {
+		return ""
+	}
+
+	a.connValues[name] = value
+	return value
+}
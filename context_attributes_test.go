@@ -0,0 +1,139 @@
+package gonvoy
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContext_Attributes(t *testing.T) {
+	newAttributesContext := func(t *testing.T, properties map[string]string) Context {
+		fc := mock_envoy.NewFilterCallbackHandler(t)
+		fc.EXPECT().GetProperty(mock.Anything).RunAndReturn(func(name string) (string, error) {
+			switch name {
+			case "connection.tls_version":
+				return "", api.ErrSerializationFailure
+			}
+
+			value, ok := properties[name]
+			if !ok {
+				return "", api.ErrValueNotFound
+			}
+
+			return value, nil
+		}).Maybe()
+
+		c, err := NewContext(fc, contextOptions{config: &internalConfig{}})
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Run("typed getters", func(t *testing.T) {
+		c := newAttributesContext(t, map[string]string{
+			"response.code":    "503",
+			"connection.mtls":  "true",
+			"request.duration": "1.5ms",
+			"request.time":     "2023-07-31T07:21:40.695646+00:00",
+			"source.address":   "10.0.0.1:54321",
+			"upstream.address": "[::1]:8080",
+			"request.path":     "/foo",
+		})
+		attrs := c.Attributes()
+
+		code, err := attrs.GetInt("response.code")
+		require.NoError(t, err)
+		assert.Equal(t, int64(503), code)
+
+		mtls, err := attrs.GetBool("connection.mtls")
+		require.NoError(t, err)
+		assert.True(t, mtls)
+
+		d, err := attrs.GetDuration("request.duration")
+		require.NoError(t, err)
+		assert.Equal(t, 1500*time.Microsecond, d)
+
+		ts, err := attrs.GetTime("request.time")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 7, 31, 7, 21, 40, 695646000, time.UTC), ts.UTC())
+
+		ip, port, err := attrs.GetAddress("source.address")
+		require.NoError(t, err)
+		assert.Equal(t, net.ParseIP("10.0.0.1"), ip)
+		assert.Equal(t, 54321, port)
+
+		ip, port, err = attrs.GetAddress("upstream.address")
+		require.NoError(t, err)
+		assert.Equal(t, net.ParseIP("::1"), ip)
+		assert.Equal(t, 8080, port)
+
+		list, err := attrs.GetList("request.path")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/foo"}, list)
+
+		_, err = attrs.GetInt("request.path")
+		assert.ErrorContains(t, err, "failed to parse attribute request.path as an integer")
+
+		_, err = attrs.GetString("response.code_details")
+		assert.ErrorIs(t, err, api.ErrValueNotFound)
+
+		_, err = attrs.GetString("connection.tls_version")
+		assert.ErrorIs(t, err, api.ErrSerializationFailure)
+	})
+
+	t.Run("map getters", func(t *testing.T) {
+		c := newAttributesContext(t, nil)
+		attrs := c.Attributes()
+
+		_, err := attrs.GetMap(AttributeResponseTrailers)
+		assert.ErrorIs(t, err, api.ErrValueNotFound)
+
+		_, err = attrs.GetMap("xds.upstream_host_metadata")
+		assert.ErrorIs(t, err, api.ErrSerializationFailure)
+
+		c.LoadResponseTrailers(&fakeHeaderMap{data: map[string][]string{"grpc-status": {"0"}, "x-foo": {"a", "b"}}})
+
+		m, err := attrs.GetMap(AttributeResponseTrailers)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{"grpc-status": {"0"}, "x-foo": {"a", "b"}}, m)
+	})
+
+	t.Run("well-known attributes", func(t *testing.T) {
+		c := newAttributesContext(t, map[string]string{
+			"request.id":                          "req-1",
+			"xds.route_name":                      "my-route",
+			"xds.cluster_name":                    "my-cluster",
+			"response.code":                       "200",
+			"source.address":                      "10.0.0.1:54321",
+			"connection.mtls":                     "true",
+			"connection.subject_peer_certificate": "CN=client",
+			"connection.uri_san_peer_certificate": "spiffe://cluster.local/ns/default/sa/client",
+		})
+		attrs := c.Attributes()
+
+		assert.Same(t, attrs, c.Attributes())
+		assert.Equal(t, "req-1", attrs.RequestID())
+		assert.Equal(t, "my-route", attrs.RouteName())
+		assert.Equal(t, "my-cluster", attrs.ClusterName())
+		assert.Equal(t, 200, attrs.ResponseCode())
+		assert.Empty(t, attrs.ResponseCodeDetails())
+		assert.True(t, attrs.MTLS())
+		assert.Empty(t, attrs.TLSVersion())
+		assert.Equal(t, "CN=client", attrs.PeerSubject())
+		assert.Equal(t, "spiffe://cluster.local/ns/default/sa/client", attrs.PeerURISAN())
+		assert.Empty(t, attrs.PeerDNSSAN())
+
+		ip, port := attrs.SourceAddress()
+		assert.Equal(t, net.ParseIP("10.0.0.1"), ip)
+		assert.Equal(t, 54321, port)
+
+		ip, port = attrs.DestinationAddress()
+		assert.Nil(t, ip)
+		assert.Zero(t, port)
+	})
+}
@@ -0,0 +1,86 @@
+package gonvoy
+
+import (
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+// FilterState is an interface for setting and getting the filter state of the current request.
+// Unlike the dynamic metadata, the filter state can be shared with the upstream connections,
+// and it can be consumed by other Envoy filters, access log formatters (e.g., `%FILTER_STATE(key)%`), and routing rules.
+type FilterState interface {
+	// Set stores the string value under the key.
+	// It defaults to a read-only value that lives within the filter chain, and is not shared with the upstream connections,
+	// use FilterStateWithStateType, FilterStateWithLifeSpan, and FilterStateWithStreamSharing to override it.
+	//
+	// Note that Envoy rejects overriding a read-only value.
+	//
+	Set(key, value string, opts ...FilterStateOption)
+
+	// Get returns the string value under the key, or an empty string if the key doesn't exist.
+	//
+	Get(key string) string
+}
+
+type FilterStateOptions struct {
+	stateType     api.StateType
+	lifeSpan      api.LifeSpan
+	streamSharing api.StreamSharing
+}
+
+type FilterStateOption func(o *FilterStateOptions)
+
+func NewFilterStateOptions(opts ...FilterStateOption) *FilterStateOptions {
+	fo := &FilterStateOptions{
+		stateType:     api.StateTypeReadOnly,
+		lifeSpan:      api.LifeSpanFilterChain,
+		streamSharing: api.None,
+	}
+
+	for _, opt := range opts {
+		opt(fo)
+	}
+
+	return fo
+}
+
+// FilterStateWithStateType specifies whether the value can be overridden later, either by this filter or another filter.
+func FilterStateWithStateType(stateType api.StateType) FilterStateOption {
+	return func(o *FilterStateOptions) {
+		o.stateType = stateType
+	}
+}
+
+// FilterStateWithLifeSpan specifies how long the value lives, e.g., api.LifeSpanRequest keeps the value across internal redirects,
+// while api.LifeSpanConnection keeps the value for the downstream connection.
+func FilterStateWithLifeSpan(lifeSpan api.LifeSpan) FilterStateOption {
+	return func(o *FilterStateOptions) {
+		o.lifeSpan = lifeSpan
+	}
+}
+
+// FilterStateWithStreamSharing specifies whether the value is shared with the upstream connection,
+// e.g., to pass the tenant ID to the upstream connection through the transport socket options.
+func FilterStateWithStreamSharing(streamSharing api.StreamSharing) FilterStateOption {
+	return func(o *FilterStateOptions) {
+		o.streamSharing = streamSharing
+	}
+}
+
+func (c *context) FilterState() FilterState {
+	return &filterState{cb: c.cb}
+}
+
+var _ FilterState = &filterState{}
+
+type filterState struct {
+	cb api.FilterCallbackHandler
+}
+
+func (s *filterState) Set(key, value string, opts ...FilterStateOption) {
+	o := NewFilterStateOptions(opts...)
+	s.cb.StreamInfo().FilterState().SetString(key, value, o.stateType, o.lifeSpan, o.streamSharing)
+}
+
+func (s *filterState) Get(key string) string {
+	return s.cb.StreamInfo().FilterState().GetString(key)
+}
@@ -0,0 +1,50 @@
+package gonvoy
+
+import (
+	"testing"
+
+	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContext_FilterState(t *testing.T) {
+	newFilterStateContext := func(t *testing.T) (Context, *mock_envoy.FilterState) {
+		filterStateMock := mock_envoy.NewFilterState(t)
+		streamInfoMock := mock_envoy.NewStreamInfo(t)
+		streamInfoMock.EXPECT().FilterState().Return(filterStateMock)
+
+		fc := mock_envoy.NewFilterCallbackHandler(t)
+		fc.EXPECT().StreamInfo().Return(streamInfoMock)
+
+		c, err := NewContext(fc, contextOptions{config: &internalConfig{}})
+		require.NoError(t, err)
+		return c, filterStateMock
+	}
+
+	t.Run("set with defaults", func(t *testing.T) {
+		c, filterStateMock := newFilterStateContext(t)
+		filterStateMock.EXPECT().SetString("tenant_id", "acme", api.StateTypeReadOnly, api.LifeSpanFilterChain, api.None).Once()
+
+		c.FilterState().Set("tenant_id", "acme")
+	})
+
+	t.Run("set with options", func(t *testing.T) {
+		c, filterStateMock := newFilterStateContext(t)
+		filterStateMock.EXPECT().SetString("tenant_id", "acme", api.StateTypeMutable, api.LifeSpanRequest, api.SharedWithUpstreamConnection).Once()
+
+		c.FilterState().Set("tenant_id", "acme",
+			FilterStateWithStateType(api.StateTypeMutable),
+			FilterStateWithLifeSpan(api.LifeSpanRequest),
+			FilterStateWithStreamSharing(api.SharedWithUpstreamConnection),
+		)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		c, filterStateMock := newFilterStateContext(t)
+		filterStateMock.EXPECT().GetString("tenant_id").Return("acme")
+
+		assert.Equal(t, "acme", c.FilterState().Get("tenant_id"))
+	})
+}
@@ -2,6 +2,7 @@ package gonvoy
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"net/http"
 	"runtime"
@@ -9,6 +10,8 @@ import (
 	"github.com/ardikabs/gonvoy/pkg/types"
 	"github.com/ardikabs/gonvoy/pkg/util"
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/protobuf/proto"
 )
 
 func (c *context) RequestHeader() Header {
@@ -60,6 +63,22 @@ func (c *context) ResponseBody() Body {
 	}
 }
 
+func (c *context) RequestTrailer() Header {
+	if c.reqTrailerMap == nil {
+		panic("The Request Trailer has not been set up yet. Likely because the HTTP request carries no trailers, or it is being accessed in an incorrect phase, such as outside of OnRequestTrailer.")
+	}
+
+	return &header{HeaderMap: c.reqTrailerMap}
+}
+
+func (c *context) ResponseTrailer() Header {
+	if c.respTrailerMap == nil {
+		panic("The Response Trailer has not been set up yet. Likely because the HTTP response carries no trailers, or it is being accessed in an incorrect phase, such as outside of OnResponseTrailer.")
+	}
+
+	return &header{HeaderMap: c.respTrailerMap}
+}
+
 func (c *context) SetRequestHost(host string) {
 	c.reqHeaderMap.SetHost(host)
 
@@ -137,6 +156,12 @@ func (c *context) LoadRequestBody(buffer api.BufferInstance, endStream bool) {
 		// - https://github.com/envoyproxy/envoy/blob/816188b86a0a52095b116b107f576324082c7c02/contrib/golang/filters/http/source/processor_state.cc#L138-L145
 		_ = buffer.Set(c.reqBufferBytes)
 
+		if _, detached := buffer.(detachedBuffer); detached {
+			// The Envoy buffer is no longer available once the trailers arrive, hence the body is read-only, see detachedBuffer.
+			c.requestBodyAccessRead = c.requestBodyAccessRead || c.requestBodyAccessWrite
+			c.requestBodyAccessWrite = false
+		}
+
 		bodyBuffer := bytes.NewBuffer(c.reqBufferBytes)
 		c.httpReq.Body = io.NopCloser(bodyBuffer)
 		c.reqBufferInstance = buffer
@@ -153,12 +178,36 @@ func (c *context) LoadResponseBody(buffer api.BufferInstance, endStream bool) {
 		// ditto with #L132-135
 		_ = buffer.Set(c.respBufferBytes)
 
+		if _, detached := buffer.(detachedBuffer); detached {
+			// ditto
+			c.responseBodyAccessRead = c.responseBodyAccessRead || c.responseBodyAccessWrite
+			c.responseBodyAccessWrite = false
+		}
+
 		bodyBuffer := bytes.NewBuffer(c.respBufferBytes)
 		c.httpResp.Body = io.NopCloser(bodyBuffer)
 		c.respBufferInstance = buffer
 	}
 }
 
+func (c *context) LoadRequestTrailers(trailer api.RequestTrailerMap) {
+	// Trailers are also exposed through the read-only http.Request
+	if c.httpReq != nil {
+		c.httpReq.Trailer = exportHeaderMap(trailer)
+	}
+
+	c.reqTrailerMap = trailer
+}
+
+func (c *context) LoadResponseTrailers(trailer api.ResponseTrailerMap) {
+	// ditto with LoadRequestTrailers
+	if c.httpResp != nil {
+		c.httpResp.Trailer = exportHeaderMap(trailer)
+	}
+
+	c.respTrailerMap = trailer
+}
+
 func (c *context) Request() *http.Request {
 	if c.httpReq == nil {
 		panic("an HTTP Request has not been set up yet. Likely because the filter has not yet traversed the HTTP request or OnRequestHeader is disabled. Please refer to the previous HTTP filter behavior.")
@@ -175,6 +224,10 @@ func (c *context) Response() *http.Response {
 	return c.httpResp
 }
 
+func (c *context) PathParam(name string) string {
+	return c.pathParams[name]
+}
+
 func (c *context) IsRequestBodyAccessible() bool {
 	return c.IsRequestBodyReadable() || c.IsRequestBodyWritable()
 }
@@ -223,6 +276,14 @@ func (c *context) IsResponseBodyWritable() bool {
 	return c.responseBodyAccessWrite
 }
 
+func (c *context) IsRequestBodyStreaming() bool {
+	return c.requestBodyStreaming
+}
+
+func (c *context) IsResponseBodyStreaming() bool {
+	return c.responseBodyStreaming
+}
+
 func (c *context) SendResponse(code int, bodyText string, opts ...LocalReplyOption) error {
 	reply := NewLocalReplyOptions(opts...)
 
@@ -269,6 +330,64 @@ func (c *context) String(code int, s string, opts ...LocalReplyOption) error {
 	return nil
 }
 
+func (c *context) Negotiate(code int, value interface{}, opts ...LocalReplyOption) error {
+	mediaType, renderer := c.negotiate()
+	body, err := renderer.Render(value)
+	if err != nil {
+		return fmt.Errorf("failed to render %s response, %w", mediaType, err)
+	}
+
+	reply := NewLocalReplyOptions(opts...)
+	if reply.headers == nil {
+		reply.headers = make(http.Header)
+	}
+
+	reply.headers.Set(HeaderContentType, contentTypeOf(mediaType))
+	reply.headers.Add(HeaderVary, HeaderAccept)
+
+	c.pcb.SendLocalReply(code, string(body), reply.headers, reply.grpcStatusCode, reply.responseCodeDetails)
+	c.committed = true
+	c.statusType = reply.statusType
+
+	return nil
+}
+
+// negotiate returns the media type and its renderer that best match the request Accept header.
+func (c *context) negotiate() (string, Renderer) {
+	n := c.contentNegotiation
+	if n == nil {
+		n = defaultContentNegotiation
+	}
+
+	var accept string
+	if c.reqHeaderMap != nil {
+		accept, _ = c.reqHeaderMap.Get(HeaderAccept)
+	}
+
+	return n.negotiate(accept)
+}
+
+func (c *context) GRPCError(grpcCode code.Code, message string, details ...proto.Message) error {
+	headers, err := newGRPCStatusHeaders(grpcCode, message, details...)
+	if err != nil {
+		return err
+	}
+
+	c.pcb.SendLocalReply(httpStatusFromGRPCCode(grpcCode), message, headers, int64(grpcCode), DefaultResponseCodeDetails)
+	c.committed = true
+	c.statusType = api.LocalReply
+
+	return nil
+}
+
+func (c *context) ErrorRenderer() ErrorRenderer {
+	if c.errorRenderer == nil {
+		return DefaultErrorRenderer
+	}
+
+	return c.errorRenderer
+}
+
 func (c *context) SkipNextPhase() error {
 	c.statusType = api.Continue
 	c.committed = true
@@ -378,6 +497,10 @@ func (c *context) isHTTPBodyAccessible(header http.Header) bool {
 	return false
 }
 
+func exportHeaderMap(hm api.HeaderMap) http.Header {
+	return (&header{HeaderMap: hm}).Export()
+}
+
 func shouldOmitContentLengthOnRequest(c Context, header api.HeaderMap) bool {
 	ctx := mustCastToContext(c)
 	if ctx.preserveContentLengthOnRequest {
@@ -0,0 +1,113 @@
+package gonvoy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+// DynamicMetadata is an interface for setting and getting the dynamic metadata of the current request within a namespace.
+// Values are converted following their JSON representation, as Envoy stores them as protobuf Struct.
+type DynamicMetadata interface {
+	// Namespace returns a DynamicMetadata that operates on the given namespace, e.g., `envoy.filters.http.rbac`.
+	//
+	Namespace(namespace string) DynamicMetadata
+
+	// Set stores the value under the key within the namespace.
+	// The value can be any JSON-serializable Go value, such as scalars, slices, maps, and structs.
+	//
+	Set(key string, value interface{}) error
+
+	// Get returns the value under the key within the namespace, and reports whether the key exists.
+	// The value is given in its JSON-decoded form, such as map[string]interface{}, []interface{}, string, float64, bool, or nil.
+	//
+	Get(key string) (interface{}, bool)
+
+	// GetInto decodes the value under the key within the namespace into the receiver, and reports whether the key exists.
+	// The receiver must be a pointer, e.g., a pointer to struct.
+	//
+	GetInto(key string, receiver interface{}) (bool, error)
+
+	// All returns all the key-value pairs within the namespace.
+	//
+	All() map[string]interface{}
+}
+
+func (c *context) DynamicMetadata() DynamicMetadata {
+	return &dynamicMetadata{
+		namespace: c.filterName,
+		cb:        c.cb,
+	}
+}
+
+var _ DynamicMetadata = &dynamicMetadata{}
+
+type dynamicMetadata struct {
+	namespace string
+	cb        api.FilterCallbackHandler
+}
+
+func (m *dynamicMetadata) Namespace(namespace string) DynamicMetadata {
+	return &dynamicMetadata{
+		namespace: namespace,
+		cb:        m.cb,
+	}
+}
+
+func (m *dynamicMetadata) Set(key string, value interface{}) error {
+	if m.namespace == "" {
+		return errors.New("dynamic metadata namespace is not set")
+	}
+
+	// Envoy only accepts the value types supported by structpb.NewValue, and panics otherwise,
+	// hence the value is normalized through its JSON representation, which also allows structs and typed maps.
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dynamic metadata %s, %w", key, err)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to marshal dynamic metadata %s, %w", key, err)
+	}
+
+	m.cb.StreamInfo().DynamicMetadata().Set(m.namespace, key, v)
+	return nil
+}
+
+func (m *dynamicMetadata) Get(key string) (interface{}, bool) {
+	v, ok := m.All()[key]
+	return v, ok
+}
+
+func (m *dynamicMetadata) GetInto(key string, receiver interface{}) (bool, error) {
+	if receiver == nil {
+		return false, ErrNilReceiver
+	}
+
+	v, ok := m.Get(key)
+	if !ok {
+		return false, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return true, fmt.Errorf("failed to unmarshal dynamic metadata %s, %w", key, err)
+	}
+
+	if err := json.Unmarshal(b, receiver); err != nil {
+		return true, fmt.Errorf("failed to unmarshal dynamic metadata %s, %w", key, err)
+	}
+
+	return true, nil
+}
+
+func (m *dynamicMetadata) All() map[string]interface{} {
+	if m.namespace == "" {
+		return nil
+	}
+
+	return m.cb.StreamInfo().DynamicMetadata().Get(m.namespace)
+}
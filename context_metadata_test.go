@@ -0,0 +1,97 @@
+package gonvoy
+
+import (
+	"testing"
+
+	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContext_DynamicMetadata(t *testing.T) {
+	type principal struct {
+		Subject string   `json:"subject"`
+		Groups  []string `json:"groups,omitempty"`
+	}
+
+	newMetadataContext := func(t *testing.T, filterName string) (Context, *mock_envoy.DynamicMetadata) {
+		metadataMock := mock_envoy.NewDynamicMetadata(t)
+		streamInfoMock := mock_envoy.NewStreamInfo(t)
+		streamInfoMock.EXPECT().DynamicMetadata().Return(metadataMock).Maybe()
+
+		fc := mock_envoy.NewFilterCallbackHandler(t)
+		fc.EXPECT().StreamInfo().Return(streamInfoMock).Maybe()
+
+		c, err := NewContext(fc, contextOptions{config: &internalConfig{filterName: filterName}})
+		require.NoError(t, err)
+		return c, metadataMock
+	}
+
+	t.Run("set under the filter name namespace", func(t *testing.T) {
+		c, metadataMock := newMetadataContext(t, "my-filter")
+		metadataMock.EXPECT().Set("my-filter", "principal", map[string]interface{}{
+			"subject": "alice",
+			"groups":  []interface{}{"admin"},
+		}).Once()
+		metadataMock.EXPECT().Set("my-filter", "score", float64(10)).Once()
+		metadataMock.EXPECT().Set("my-filter", "labels", map[string]interface{}{"tier": "gold"}).Once()
+
+		require.NoError(t, c.DynamicMetadata().Set("principal", principal{Subject: "alice", Groups: []string{"admin"}}))
+		require.NoError(t, c.DynamicMetadata().Set("score", 10))
+		require.NoError(t, c.DynamicMetadata().Set("labels", map[string]string{"tier": "gold"}))
+	})
+
+	t.Run("set under another namespace", func(t *testing.T) {
+		c, metadataMock := newMetadataContext(t, "my-filter")
+		metadataMock.EXPECT().Set("envoy.filters.http.rbac", "allowed", true).Once()
+
+		require.NoError(t, c.DynamicMetadata().Namespace("envoy.filters.http.rbac").Set("allowed", true))
+	})
+
+	t.Run("set an unsupported value", func(t *testing.T) {
+		c, _ := newMetadataContext(t, "my-filter")
+		assert.Error(t, c.DynamicMetadata().Set("fn", func() {}))
+	})
+
+	t.Run("set without namespace", func(t *testing.T) {
+		c, _ := newMetadataContext(t, "")
+		assert.ErrorContains(t, c.DynamicMetadata().Set("foo", "bar"), "dynamic metadata namespace is not set")
+	})
+
+	t.Run("get", func(t *testing.T) {
+		c, metadataMock := newMetadataContext(t, "my-filter")
+		metadataMock.EXPECT().Get("my-filter").Return(map[string]interface{}{
+			"principal": map[string]interface{}{"subject": "alice", "groups": []interface{}{"admin"}},
+			"score":     float64(10),
+		})
+
+		md := c.DynamicMetadata()
+
+		v, ok := md.Get("score")
+		assert.True(t, ok)
+		assert.Equal(t, float64(10), v)
+
+		_, ok = md.Get("missing")
+		assert.False(t, ok)
+
+		var p principal
+		ok, err := md.GetInto("principal", &p)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, principal{Subject: "alice", Groups: []string{"admin"}}, p)
+
+		ok, err = md.GetInto("missing", &p)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		var s string
+		ok, err = md.GetInto("score", &s)
+		assert.True(t, ok)
+		assert.Error(t, err)
+
+		_, err = md.GetInto("score", nil)
+		assert.ErrorIs(t, err, ErrNilReceiver)
+
+		assert.Len(t, md.All(), 2)
+	})
+}
@@ -2,6 +2,7 @@ package gonvoy
 
 import (
 	"errors"
+	"net/http"
 	"testing"
 
 	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
@@ -155,4 +156,54 @@ func TestContext(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("Body streaming", func(t *testing.T) {
+		ctx := fakeDummyContext(t, &internalConfig{})
+		assert.False(t, ctx.IsRequestBodyStreaming())
+		assert.False(t, ctx.IsResponseBodyStreaming())
+
+		ctx = fakeDummyContext(t, &internalConfig{
+			requestBodyStreaming:  true,
+			responseBodyStreaming: true,
+		})
+		assert.True(t, ctx.IsRequestBodyStreaming())
+		assert.True(t, ctx.IsResponseBodyStreaming())
+	})
+
+	t.Run("Detached body is read-only", func(t *testing.T) {
+		ctx := fakeDummyContext(t, &internalConfig{
+			allowRequestBodyWrite:  true,
+			allowResponseBodyWrite: true,
+		})
+		ctx.(*context).httpReq = &http.Request{}
+		ctx.(*context).httpResp = &http.Response{}
+
+		ctx.(*context).reqBufferBytes = []byte("lorem")
+		ctx.LoadRequestBody(detachedBuffer{}, true)
+		assert.False(t, ctx.IsRequestBodyWritable())
+		assert.True(t, ctx.IsRequestBodyReadable())
+		assert.Equal(t, "lorem", ctx.RequestBody().String())
+
+		ctx.(*context).respBufferBytes = []byte("ipsum")
+		ctx.LoadResponseBody(detachedBuffer{}, true)
+		assert.False(t, ctx.IsResponseBodyWritable())
+		assert.True(t, ctx.IsResponseBodyReadable())
+		assert.Equal(t, "ipsum", ctx.ResponseBody().String())
+	})
+
+	t.Run("Trailers", func(t *testing.T) {
+		ctx := fakeDummyContext(t, &internalConfig{})
+
+		assert.Panics(t, func() { ctx.RequestTrailer() })
+		assert.Panics(t, func() { ctx.ResponseTrailer() })
+
+		reqTrailer := &fakeHeaderMap{data: map[string][]string{"x-checksum": {"foo"}}}
+		ctx.LoadRequestTrailers(reqTrailer)
+		assert.Equal(t, "foo", ctx.RequestTrailer().Export().Get("x-checksum"))
+
+		respTrailer := &fakeHeaderMap{data: map[string][]string{"grpc-status": {"0"}}}
+		ctx.LoadResponseTrailers(respTrailer)
+		ctx.ResponseTrailer().Set("grpc-status", "13")
+		assert.Equal(t, []string{"13"}, respTrailer.data["grpc-status"])
+	})
 }
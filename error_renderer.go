@@ -0,0 +1,90 @@
+package gonvoy
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// DefaultErrorRenderer is the ErrorRenderer used when ConfigOptions.ErrorRenderer is unset.
+var DefaultErrorRenderer ErrorRenderer = NegotiatedErrorRenderer{}
+
+// ErrorRenderer renders an HTTPError into the body of an error response, along with its content type.
+// It is used by the DefaultErrorHandler and the error mappings, while a custom ErrorHandler retrieves it through Context.ErrorRenderer.
+// See ConfigOptions.ErrorRenderer.
+type ErrorRenderer interface {
+	// Render renders the HTTPError into the response body, returning the body and its content type.
+	//
+	Render(c Context, e *HTTPError) (body []byte, contentType string, err error)
+}
+
+// ErrorRendererFunc is an adapter to allow the use of an ordinary function as an ErrorRenderer.
+type ErrorRendererFunc func(c Context, e *HTTPError) ([]byte, string, error)
+
+func (fn ErrorRendererFunc) Render(c Context, e *HTTPError) ([]byte, string, error) {
+	return fn(c, e)
+}
+
+// MinimalJSONErrorRenderer renders the HTTPError into the minimal JSON response, see NewMinimalJSONResponse.
+// The extension members of the HTTPError are not rendered.
+type MinimalJSONErrorRenderer struct{}
+
+func (MinimalJSONErrorRenderer) Render(c Context, e *HTTPError) ([]byte, string, error) {
+	return NewMinimalJSONResponse(e.code(), e.message()), MIMEApplicationJSON, nil
+}
+
+// ProblemJSONErrorRenderer renders the HTTPError into a problem details JSON response, see RFC 7807, such as:
+//
+//	{
+//	  "type": "https://example.com/problems/quota-exceeded",
+//	  "title": "Too Many Requests",
+//	  "status": 429,
+//	  "detail": "Quota Exceeded",
+//	  "instance": "/v1/orders",
+//	  "code": "QUOTA_EXCEEDED"
+//	}
+//
+// The code, along with the HTTPError.Extensions, are rendered as the extension members,
+// while the extension members never override the standard members.
+type ProblemJSONErrorRenderer struct {
+	// TypeBaseURI is the base URI of the problem types, where the type is the base URI followed by the error code in lower kebab case.
+	// When empty, the type is `about:blank`.
+	TypeBaseURI string
+}
+
+func (r ProblemJSONErrorRenderer) Render(c Context, e *HTTPError) ([]byte, string, error) {
+	status := e.statusCode()
+	problem := make(map[string]interface{}, len(e.Extensions)+6)
+	for key, value := range e.Extensions {
+		problem[key] = value
+	}
+
+	problem["code"] = e.code()
+	problem["type"] = r.problemType(e)
+	problem["title"] = http.StatusText(status)
+	problem["status"] = status
+
+	if e.Message != "" {
+		problem["detail"] = e.Message
+	}
+
+	if path, _ := c.GetProperty("request.path", ""); path != "" {
+		path, _, _ = strings.Cut(path, "?")
+		problem["instance"] = path
+	}
+
+	body, err := json.Marshal(problem)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return body, MIMEApplicationProblemJSON, nil
+}
+
+func (r ProblemJSONErrorRenderer) problemType(e *HTTPError) string {
+	if r.TypeBaseURI == "" {
+		return "about:blank"
+	}
+
+	return strings.TrimSuffix(r.TypeBaseURI, "/") + "/" + strings.ToLower(strings.ReplaceAll(e.code(), "_", "-"))
+}
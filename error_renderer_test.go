@@ -0,0 +1,123 @@
+package gonvoy
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorRenderer(t *testing.T) {
+	newRendererContext := func(t *testing.T, renderer ErrorRenderer) (Context, *mock_envoy.FilterCallbackHandler) {
+		fc := mock_envoy.NewFilterCallbackHandler(t)
+		fc.EXPECT().GetProperty("request.path").Return("/v1/orders?limit=10", nil).Maybe()
+
+		c, err := NewContext(fc, contextOptions{config: &internalConfig{errorRenderer: renderer}})
+		require.NoError(t, err)
+		return c, fc
+	}
+
+	t.Run("default renderer falls back to the minimal JSON response", func(t *testing.T) {
+		c, _ := newRendererContext(t, nil)
+		assert.Equal(t, DefaultErrorRenderer, c.ErrorRenderer())
+
+		body, contentType, err := c.ErrorRenderer().Render(c, &HTTPError{StatusCode: http.StatusTooManyRequests})
+		require.NoError(t, err)
+		assert.Equal(t, MIMEApplicationJSON, contentType)
+
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &resp))
+		assert.Equal(t, "TOO_MANY_REQUESTS", resp["code"])
+		assert.Equal(t, "Too Many Requests", resp["message"])
+	})
+
+	t.Run("problem JSON renderer", func(t *testing.T) {
+		testcases := []struct {
+			name     string
+			renderer ProblemJSONErrorRenderer
+			err      *HTTPError
+			expected map[string]interface{}
+		}{
+			{
+				name:     "about:blank type",
+				renderer: ProblemJSONErrorRenderer{},
+				err:      &HTTPError{StatusCode: http.StatusForbidden},
+				expected: map[string]interface{}{
+					"type":     "about:blank",
+					"title":    "Forbidden",
+					"status":   float64(http.StatusForbidden),
+					"instance": "/v1/orders",
+					"code":     "FORBIDDEN",
+				},
+			},
+			{
+				name:     "type from the error code, along with the extension members",
+				renderer: ProblemJSONErrorRenderer{TypeBaseURI: "https://example.com/problems/"},
+				err: &HTTPError{
+					StatusCode: http.StatusTooManyRequests,
+					Code:       "QUOTA_EXCEEDED",
+					Message:    "Quota of 100 requests has been exceeded",
+					Extensions: map[string]interface{}{"limit": 100, "status": "overridden"},
+				},
+				expected: map[string]interface{}{
+					"type":     "https://example.com/problems/quota-exceeded",
+					"title":    "Too Many Requests",
+					"status":   float64(http.StatusTooManyRequests),
+					"detail":   "Quota of 100 requests has been exceeded",
+					"instance": "/v1/orders",
+					"code":     "QUOTA_EXCEEDED",
+					"limit":    float64(100),
+				},
+			},
+		}
+
+		for _, tc := range testcases {
+			t.Run(tc.name, func(t *testing.T) {
+				c, _ := newRendererContext(t, tc.renderer)
+
+				body, contentType, err := c.ErrorRenderer().Render(c, tc.err)
+				require.NoError(t, err)
+				assert.Equal(t, MIMEApplicationProblemJSON, contentType)
+
+				var problem map[string]interface{}
+				require.NoError(t, json.Unmarshal(body, &problem))
+				assert.Equal(t, tc.expected, problem)
+			})
+		}
+	})
+
+	t.Run("default error handler uses the configured renderer", func(t *testing.T) {
+		c, fc := newRendererContext(t, ProblemJSONErrorRenderer{})
+		fc.EXPECT().GetProperty(mock.Anything).Return("-", nil)
+		fc.EXPECT().Log(mock.Anything, mock.Anything).Maybe()
+		fc.EXPECT().LogLevel().Return(api.Error).Maybe()
+
+		dcb := mock_envoy.NewDecoderFilterCallbacks(t)
+		dcb.EXPECT().SendLocalReply(http.StatusUnauthorized, mock.Anything, mock.Anything, int64(-1), mock.Anything).
+			Run(func(code int, body string, headers map[string][]string, grpcStatus int64, details string) {
+				assert.Contains(t, body, `"type":"about:blank"`)
+				assert.Contains(t, body, `"code":"UNAUTHORIZED"`)
+				assert.Equal(t, []string{MIMEApplicationProblemJSON}, headers[HeaderContentType])
+				assert.Equal(t, []string{"gateway"}, headers["Reporter"])
+			})
+		c.(*context).pcb = dcb
+
+		assert.Equal(t, api.LocalReply, DefaultErrorHandler(c, ErrUnauthorized))
+	})
+
+	t.Run("error renderer func", func(t *testing.T) {
+		renderer := ErrorRendererFunc(func(c Context, e *HTTPError) ([]byte, string, error) {
+			return []byte(e.code()), MIMETextPlainCharsetUTF8, nil
+		})
+
+		body, contentType, err := renderer.Render(nil, &HTTPError{StatusCode: http.StatusNotFound})
+		require.NoError(t, err)
+		assert.Equal(t, "NOT_FOUND", string(body))
+		assert.Equal(t, MIMETextPlainCharsetUTF8, contentType)
+	})
+}
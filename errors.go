@@ -1,6 +1,10 @@
 package gonvoy
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
 
 var (
 	// List of errors related to HTTP operations.
@@ -18,3 +22,108 @@ var (
 	ErrIncompatibleReceiver  = errors.New("receiver and value has an incompatible type")
 	ErrNilReceiver           = errors.New("receiver shouldn't be nil")
 )
+
+// HTTPError represents an error that is rendered into an HTTP response by the DefaultErrorHandler, see ErrorRenderer,
+// carrying the response details, while the cause is kept for the logs.
+//
+// Example usage:
+//
+//	func (h *Handler) OnRequestHeader(c gonvoy.Context) error {
+//		...
+//		return &gonvoy.HTTPError{
+//			StatusCode: http.StatusTooManyRequests,
+//			Code:       "QUOTA_EXCEEDED",
+//			Message:    "Quota Exceeded",
+//			Headers:    http.Header{"Retry-After": {"60"}},
+//			Cause:      err,
+//		}
+//	}
+type HTTPError struct {
+	// StatusCode is the HTTP status code of the response. It defaults to 500 (Internal Server Error).
+	StatusCode int
+
+	// Code is the machine-readable error code, e.g., `QUOTA_EXCEEDED`.
+	// It defaults to the status text in upper snake case, e.g., `TOO_MANY_REQUESTS`,
+	// or `ERROR` when the status code has no status text, e.g., 499.
+	Code string
+
+	// Message is the human-readable error message. It defaults to the status text, or `Error` when there is none.
+	Message string
+
+	// Headers are the extra headers of the response.
+	Headers http.Header
+
+	// Extensions are the additional members of the response, which are rendered by the ProblemJSONErrorRenderer.
+	Extensions map[string]interface{}
+
+	// Body overrides the response body, which defaults to the rendered HTTPError, see ConfigOptions.ErrorRenderer.
+	// It is sent as a JSON response, unless the Headers carry a Content-Type.
+	Body []byte
+
+	// ResponseCodeDetails is the Envoy response code details, see ResponseCodeDetailPrefix.
+	// It defaults to the error wrapped with DefaultResponseCodeDetailError.
+	ResponseCodeDetails string
+
+	// GRPCStatus is the gRPC status code of the response, if any.
+	// For the gRPC requests, it defaults to the gRPC status code mapped from the StatusCode, see GRPCCodeFromHTTPStatus,
+	// while the Message is sent as the grpc-message instead of the response body.
+	GRPCStatus int64
+
+	// Cause is the underlying error, which is never exposed on the response.
+	Cause error
+}
+
+// NewHTTPError creates an HTTPError with the given status code, error code, and message.
+func NewHTTPError(statusCode int, code, message string) *HTTPError {
+	return &HTTPError{
+		StatusCode: statusCode,
+		Code:       code,
+		Message:    message,
+	}
+}
+
+func (e *HTTPError) Error() string {
+	msg := e.message()
+	if e.Cause != nil {
+		return msg + ", " + e.Cause.Error()
+	}
+
+	return msg
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Cause
+}
+
+func (e *HTTPError) statusCode() int {
+	if e.StatusCode == 0 {
+		return http.StatusInternalServerError
+	}
+
+	return e.StatusCode
+}
+
+func (e *HTTPError) code() string {
+	if e.Code != "" {
+		return e.Code
+	}
+
+	text := http.StatusText(e.statusCode())
+	if text == "" {
+		return "ERROR"
+	}
+
+	return strings.ToUpper(strings.ReplaceAll(text, " ", "_"))
+}
+
+func (e *HTTPError) message() string {
+	if e.Message != "" {
+		return e.Message
+	}
+
+	if text := http.StatusText(e.statusCode()); text != "" {
+		return text
+	}
+
+	return "Error"
+}
@@ -1,6 +1,8 @@
 package mystats
 
 import (
+	"time"
+
 	"github.com/ardikabs/gonvoy"
 	"github.com/ardikabs/gonvoy/pkg/envoy"
 )
@@ -37,5 +39,12 @@ func (f Filter) OnComplete(c gonvoy.Context) error {
 		"route_name", gonvoy.MustGetProperty(c, "xds.route_name", "-"),
 	).Increment(1)
 
+	if d, err := time.ParseDuration(gonvoy.MustGetProperty(c, "request.duration", "")); err == nil {
+		c.Metrics().Histogram("request_duration_milliseconds",
+			"host", gonvoy.MustGetProperty(c, "request.host", "-"),
+			"route_name", gonvoy.MustGetProperty(c, "xds.route_name", "-"),
+		).Record(uint64(d.Milliseconds()))
+	}
+
 	return nil
 }
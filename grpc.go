@@ -0,0 +1,175 @@
+package gonvoy
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// GRPCCodeFromHTTPStatus returns the gRPC status code corresponding to the HTTP status code,
+// e.g., 401 (Unauthorized) to UNAUTHENTICATED, or 503 (Service Unavailable) to UNAVAILABLE.
+// It returns UNKNOWN for the HTTP status codes without a counterpart.
+func GRPCCodeFromHTTPStatus(statusCode int) code.Code {
+	switch statusCode {
+	case http.StatusOK:
+		return code.Code_OK
+	case http.StatusBadRequest:
+		return code.Code_INVALID_ARGUMENT
+	case http.StatusUnauthorized:
+		return code.Code_UNAUTHENTICATED
+	case http.StatusForbidden:
+		return code.Code_PERMISSION_DENIED
+	case http.StatusNotFound:
+		return code.Code_NOT_FOUND
+	case http.StatusConflict:
+		return code.Code_ABORTED
+	case http.StatusPreconditionFailed:
+		return code.Code_FAILED_PRECONDITION
+	case http.StatusRequestedRangeNotSatisfiable:
+		return code.Code_OUT_OF_RANGE
+	case http.StatusTooManyRequests:
+		return code.Code_RESOURCE_EXHAUSTED
+	case 499: // Client Closed Request
+		return code.Code_CANCELLED
+	case http.StatusInternalServerError:
+		return code.Code_INTERNAL
+	case http.StatusNotImplemented:
+		return code.Code_UNIMPLEMENTED
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return code.Code_UNAVAILABLE
+	case http.StatusGatewayTimeout:
+		return code.Code_DEADLINE_EXCEEDED
+	default:
+		return code.Code_UNKNOWN
+	}
+}
+
+// httpStatusFromGRPCCode returns the HTTP status code corresponding to the gRPC status code.
+// It is only observed by the non-gRPC clients, since Envoy replies the gRPC requests with 200 (OK).
+func httpStatusFromGRPCCode(c code.Code) int {
+	switch c {
+	case code.Code_OK:
+		return http.StatusOK
+	case code.Code_CANCELLED:
+		return 499
+	case code.Code_INVALID_ARGUMENT, code.Code_OUT_OF_RANGE:
+		return http.StatusBadRequest
+	case code.Code_FAILED_PRECONDITION:
+		return http.StatusPreconditionFailed
+	case code.Code_UNAUTHENTICATED:
+		return http.StatusUnauthorized
+	case code.Code_PERMISSION_DENIED:
+		return http.StatusForbidden
+	case code.Code_NOT_FOUND:
+		return http.StatusNotFound
+	case code.Code_ALREADY_EXISTS, code.Code_ABORTED:
+		return http.StatusConflict
+	case code.Code_RESOURCE_EXHAUSTED:
+		return http.StatusTooManyRequests
+	case code.Code_UNIMPLEMENTED:
+		return http.StatusNotImplemented
+	case code.Code_UNAVAILABLE:
+		return http.StatusServiceUnavailable
+	case code.Code_DEADLINE_EXCEEDED:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// isGRPCContentType reports whether the content type is a gRPC content type, e.g., `application/grpc` or `application/grpc+proto`.
+func isGRPCContentType(contentType string) bool {
+	rest, ok := strings.CutPrefix(strings.ToLower(contentType), MIMEApplicationGRPC)
+	return ok && (rest == "" || rest[0] == '+' || rest[0] == ';')
+}
+
+// isGRPCRequest reports whether the request of the HTTP filter context is a gRPC request.
+func isGRPCRequest(c Context) bool {
+	fCtx, ok := c.(*context)
+	if !ok || fCtx.reqHeaderMap == nil {
+		return false
+	}
+
+	contentType, _ := fCtx.reqHeaderMap.Get(HeaderContentType)
+	return isGRPCContentType(contentType)
+}
+
+// newGRPCStatusHeaders returns the grpc-message and grpc-status-details-bin headers of the gRPC status.
+// The grpc-status-details-bin header is only set when the status carries details.
+func newGRPCStatusHeaders(c code.Code, message string, details ...proto.Message) (http.Header, error) {
+	headers := make(http.Header, 2)
+	if message != "" {
+		headers[HeaderGRPCMessage] = []string{encodeGRPCMessage(message)}
+	}
+
+	if len(details) == 0 {
+		return headers, nil
+	}
+
+	st := &status.Status{Code: int32(c), Message: message}
+	for _, detail := range details {
+		d, err := anypb.New(detail)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode gRPC status details, %w", err)
+		}
+
+		st.Details = append(st.Details, d)
+	}
+
+	b, err := proto.Marshal(st)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode gRPC status, %w", err)
+	}
+
+	headers[HeaderGRPCStatusDetails] = []string{base64.RawStdEncoding.EncodeToString(b)}
+	return headers, nil
+}
+
+// encodeGRPCMessage percent-encodes the gRPC message, as specified by the gRPC over HTTP2 protocol.
+func encodeGRPCMessage(message string) string {
+	var sb strings.Builder
+	for i := 0; i < len(message); i++ {
+		ch := message[i]
+		if ch >= ' ' && ch <= '~' && ch != '%' {
+			sb.WriteByte(ch)
+			continue
+		}
+
+		fmt.Fprintf(&sb, "%%%02X", ch)
+	}
+
+	return sb.String()
+}
+
+// sendGRPCError sends the HTTPError as a gRPC local reply, where the message is sent as the grpc-message.
+// The gRPC status code defaults to the one mapped from the HTTP status code, see GRPCCodeFromHTTPStatus.
+func sendGRPCError(c Context, httpErr *HTTPError, opts ...LocalReplyOption) error {
+	grpcCode := GRPCCodeFromHTTPStatus(httpErr.statusCode())
+	if httpErr.GRPCStatus > 0 {
+		grpcCode = code.Code(httpErr.GRPCStatus)
+	}
+
+	message := httpErr.message()
+	headers, err := newGRPCStatusHeaders(grpcCode, message)
+	if err != nil {
+		return err
+	}
+
+	replyHeaders := NewGatewayHeaders()
+	for key, values := range httpErr.Headers {
+		replyHeaders[key] = values
+	}
+
+	for key, values := range headers {
+		replyHeaders[key] = values
+	}
+
+	opts = append(opts, LocalReplyWithGRPCStatus(int64(grpcCode)), LocalReplyWithHTTPHeaders(replyHeaders))
+	return c.SendResponse(httpErr.statusCode(), message, opts...)
+}
@@ -0,0 +1,136 @@
+package gonvoy
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGRPCCodeFromHTTPStatus(t *testing.T) {
+	testcases := []struct {
+		statusCode int
+		expected   code.Code
+	}{
+		{statusCode: http.StatusBadRequest, expected: code.Code_INVALID_ARGUMENT},
+		{statusCode: http.StatusUnauthorized, expected: code.Code_UNAUTHENTICATED},
+		{statusCode: http.StatusForbidden, expected: code.Code_PERMISSION_DENIED},
+		{statusCode: http.StatusTooManyRequests, expected: code.Code_RESOURCE_EXHAUSTED},
+		{statusCode: 499, expected: code.Code_CANCELLED},
+		{statusCode: http.StatusInternalServerError, expected: code.Code_INTERNAL},
+		{statusCode: http.StatusBadGateway, expected: code.Code_UNAVAILABLE},
+		{statusCode: http.StatusServiceUnavailable, expected: code.Code_UNAVAILABLE},
+		{statusCode: http.StatusTeapot, expected: code.Code_UNKNOWN},
+	}
+
+	for _, tc := range testcases {
+		t.Run(http.StatusText(tc.statusCode), func(t *testing.T) {
+			assert.Equal(t, tc.expected, GRPCCodeFromHTTPStatus(tc.statusCode))
+		})
+	}
+}
+
+func TestIsGRPCContentType(t *testing.T) {
+	assert.True(t, isGRPCContentType("application/grpc"))
+	assert.True(t, isGRPCContentType("application/grpc+proto"))
+	assert.True(t, isGRPCContentType("Application/gRPC; charset=utf-8"))
+	assert.False(t, isGRPCContentType("application/grpc-web"))
+	assert.False(t, isGRPCContentType("application/json"))
+	assert.False(t, isGRPCContentType(""))
+}
+
+func TestEncodeGRPCMessage(t *testing.T) {
+	assert.Equal(t, "Unauthorized", encodeGRPCMessage("Unauthorized"))
+	assert.Equal(t, "100%25 d%C3%A9j%C3%A0 vu%0A", encodeGRPCMessage("100% déjà vu\n"))
+}
+
+func TestContext_GRPCError(t *testing.T) {
+	newGRPCContext := func(t *testing.T, contentType string) (Context, *mock_envoy.FilterCallbackHandler, *mock_envoy.DecoderFilterCallbacks) {
+		fc := mock_envoy.NewFilterCallbackHandler(t)
+		c, err := NewContext(fc, contextOptions{config: &internalConfig{}})
+		require.NoError(t, err)
+
+		reqHeaderMap := mock_envoy.NewRequestHeaderMap(t)
+		reqHeaderMap.EXPECT().Get(HeaderContentType).Return(contentType, true).Maybe()
+
+		dcb := mock_envoy.NewDecoderFilterCallbacks(t)
+		c.(*context).reqHeaderMap = reqHeaderMap
+		c.(*context).pcb = dcb
+		return c, fc, dcb
+	}
+
+	t.Run("with status details", func(t *testing.T) {
+		c, _, dcb := newGRPCContext(t, MIMEApplicationGRPC)
+		detail := &errdetails.ErrorInfo{Reason: "TOKEN_EXPIRED", Domain: "example.com"}
+
+		dcb.EXPECT().SendLocalReply(http.StatusUnauthorized, "token expired", mock.Anything, int64(code.Code_UNAUTHENTICATED), mock.Anything).
+			Run(func(code int, body string, headers map[string][]string, grpcStatus int64, details string) {
+				assert.Equal(t, []string{"token expired"}, headers[HeaderGRPCMessage])
+				require.Len(t, headers[HeaderGRPCStatusDetails], 1)
+
+				b, err := base64.RawStdEncoding.DecodeString(headers[HeaderGRPCStatusDetails][0])
+				require.NoError(t, err)
+
+				st := &status.Status{}
+				require.NoError(t, proto.Unmarshal(b, st))
+				assert.Equal(t, int32(16), st.GetCode())
+				assert.Equal(t, "token expired", st.GetMessage())
+				require.Len(t, st.GetDetails(), 1)
+
+				info := &errdetails.ErrorInfo{}
+				require.NoError(t, st.GetDetails()[0].UnmarshalTo(info))
+				assert.True(t, proto.Equal(detail, info))
+			})
+
+		require.NoError(t, c.GRPCError(code.Code_UNAUTHENTICATED, "token expired", detail))
+		assert.True(t, c.Committed())
+	})
+
+	t.Run("without status details", func(t *testing.T) {
+		c, _, dcb := newGRPCContext(t, MIMEApplicationGRPC)
+		dcb.EXPECT().SendLocalReply(http.StatusServiceUnavailable, "try again later", mock.Anything, int64(code.Code_UNAVAILABLE), mock.Anything).
+			Run(func(code int, body string, headers map[string][]string, grpcStatus int64, details string) {
+				assert.NotContains(t, headers, HeaderGRPCStatusDetails)
+			})
+
+		require.NoError(t, c.GRPCError(code.Code_UNAVAILABLE, "try again later"))
+	})
+
+	t.Run("default error handler maps the error into the gRPC status", func(t *testing.T) {
+		c, fc, dcb := newGRPCContext(t, "application/grpc+proto")
+		fc.EXPECT().GetProperty(mock.Anything).Return("-", nil)
+		fc.EXPECT().Log(mock.Anything, mock.Anything).Maybe()
+		fc.EXPECT().LogLevel().Return(api.Error).Maybe()
+
+		dcb.EXPECT().SendLocalReply(http.StatusForbidden, "Forbidden", mock.Anything, int64(code.Code_PERMISSION_DENIED), mock.Anything).
+			Run(func(code int, body string, headers map[string][]string, grpcStatus int64, details string) {
+				assert.Equal(t, []string{"Forbidden"}, headers[HeaderGRPCMessage])
+				assert.Equal(t, []string{"gateway"}, headers["Reporter"])
+				assert.NotContains(t, headers, HeaderContentType)
+			})
+
+		assert.Equal(t, api.LocalReply, DefaultErrorHandler(c, ErrAccessDenied))
+	})
+
+	t.Run("explicit gRPC status of the HTTPError wins", func(t *testing.T) {
+		c, fc, dcb := newGRPCContext(t, MIMEApplicationGRPC)
+		fc.EXPECT().GetProperty(mock.Anything).Return("-", nil)
+		fc.EXPECT().Log(mock.Anything, mock.Anything).Maybe()
+		fc.EXPECT().LogLevel().Return(api.Error).Maybe()
+
+		dcb.EXPECT().SendLocalReply(http.StatusTooManyRequests, "Quota Exceeded", mock.Anything, int64(code.Code_UNAVAILABLE), mock.Anything)
+
+		httpErr := NewHTTPError(http.StatusTooManyRequests, "QUOTA_EXCEEDED", "Quota Exceeded")
+		httpErr.GRPCStatus = int64(code.Code_UNAVAILABLE)
+		assert.Equal(t, api.LocalReply, DefaultErrorHandler(c, httpErr))
+	})
+}
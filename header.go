@@ -7,8 +7,12 @@ import (
 )
 
 const (
+	HeaderAccept              = "Accept"
 	HeaderContentLength       = "Content-Length"
 	HeaderContentType         = "Content-Type"
+	HeaderGRPCMessage         = "grpc-message"
+	HeaderGRPCStatusDetails   = "grpc-status-details-bin"
+	HeaderVary                = "Vary"
 	HeaderXRequestBodyAccess  = "X-Request-Body-Access"
 	HeaderXResponseBodyAccess = "X-Response-Body-Access"
 	HeaderXContentOperation   = "X-Content-Operation"
@@ -26,6 +30,7 @@ const (
 	// MIME types
 	MIMEApplicationJSON            = "application/json"
 	MIMEApplicationJSONCharsetUTF8 = MIMEApplicationJSON + "; " + charsetUTF8
+	MIMEApplicationProblemJSON     = "application/problem+json"
 	MIMEApplicationXML             = "application/xml"
 	MIMEApplicationXMLCharsetUTF8  = MIMEApplicationXML + "; " + charsetUTF8
 	MIMEApplicationGRPC            = "application/grpc"
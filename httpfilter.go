@@ -1,10 +1,12 @@
 package gonvoy
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ardikabs/gonvoy/pkg/util"
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/go-logr/logr"
 )
 
 var NoOpHttpFilter = &api.PassThroughStreamFilter{}
@@ -19,6 +21,28 @@ var NoOpHttpFilter = &api.PassThroughStreamFilter{}
 // to inject dependencies into this struct, if required by the handler.
 type HttpFilterFactoryFunc func() HttpFilter
 
+// TypedHttpFilterFactoryFunc defines a factory func for creating an HttpFilter with its filter configuration.
+//
+// It is similar to HttpFilterFactoryFunc, except it receives the filter configuration,
+// which has been parsed, validated, and merged with the parent filter configuration (if any),
+// hence there is no need to retrieve and type-assert the filter configuration on OnBegin.
+// The filter configuration is shared across requests, thus it must be treated as read-only.
+type TypedHttpFilterFactoryFunc[C any] func(cfg *C, deps Deps) HttpFilter
+
+// Deps holds the dependencies that are injected to the TypedHttpFilterFactoryFunc.
+type Deps struct {
+	// Cache is the cache shared across requests within the same filter configuration, see RuntimeContext.GetCache.
+	Cache Cache
+
+	// Metrics is the metric registry shared across requests within the same filter configuration, see RuntimeContext.Metrics.
+	Metrics Metrics
+
+	// Logger is the logger bound to the current request, see RuntimeContext.Log.
+	// Unlike Cache and Metrics, it is renewed for every request, hence it must not be kept beyond the request,
+	// e.g., within the filter configuration, use ConfigContext.Log on OnConfigInit instead.
+	Logger logr.Logger
+}
+
 // HttpFilter defines an interface for an HTTP filter used in Envoy.
 // It provides methods for managing filter names, startup, and completion.
 // This interface is specifically designed as a mechanism for onboarding the user HTTP filters to Envoy.
@@ -46,11 +70,59 @@ type HttpFilter interface {
 	OnComplete(c Context) error
 }
 
+// filterBuilder creates a new HttpFilter for the given request.
+type filterBuilder func(c RuntimeContext) (HttpFilter, error)
+
 func NewHttpFilterFactory(filterFactoryFunc HttpFilterFactoryFunc) api.StreamFilterFactory {
 	if util.IsNil(filterFactoryFunc()) {
 		panic("httpFilterFactory: filterFactoryFunc shouldn't return nil")
 	}
 
+	return newHttpFilterFactory(func(c RuntimeContext) (HttpFilter, error) {
+		return filterFactoryFunc(), nil
+	})
+}
+
+// NewTypedHttpFilterFactory is similar to NewHttpFilterFactory, except it is using TypedHttpFilterFactoryFunc.
+// The ConfigOptions.FilterConfig of the filter is expected to be either C or *C.
+func NewTypedHttpFilterFactory[C any](filterFactoryFunc TypedHttpFilterFactoryFunc[C]) api.StreamFilterFactory {
+	if filterFactoryFunc == nil {
+		panic("httpFilterFactory: filterFactoryFunc shouldn't be nil")
+	}
+
+	return newHttpFilterFactory(filterFactoryFunc.build)
+}
+
+func (fn TypedHttpFilterFactoryFunc[C]) build(c RuntimeContext) (HttpFilter, error) {
+	cfg, err := asTypedFilterConfig[C](c.GetFilterConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	filter := fn(cfg, Deps{
+		Cache:   c.GetCache(),
+		Metrics: c.Metrics(),
+		Logger:  c.Log(),
+	})
+	if util.IsNil(filter) {
+		return nil, errors.New("filterFactoryFunc shouldn't return nil")
+	}
+
+	return filter, nil
+}
+
+// asTypedFilterConfig returns the filter config as *C.
+// When the filter config is omitted, it is built from its defaults during the parse, see ConfigOptions.FilterConfig.
+func asTypedFilterConfig[C any](filterConfig interface{}) (*C, error) {
+	cfg, ok := filterConfig.(*C)
+	if !ok {
+		return nil, fmt.Errorf("unexpected filter config type '%T', expecting '%T'", filterConfig, cfg)
+	}
+
+	return cfg, nil
+}
+
+func newHttpFilterFactory(newFilter filterBuilder) api.StreamFilterFactory {
 	return func(cfg interface{}, cb api.FilterCallbackHandler) api.StreamFilter {
 		config, ok := cfg.(*internalConfig)
 		if !ok {
@@ -67,26 +139,33 @@ func NewHttpFilterFactory(filterFactoryFunc HttpFilterFactoryFunc) api.StreamFil
 			return NoOpHttpFilter
 		}
 
-		manager, err := buildHttpFilterManager(ctx, filterFactoryFunc)
+		manager, err := buildHttpFilterManager(ctx, newFilter)
 		if err != nil {
 			logger.Error(err, "failed to build HTTP filter manager, ignoring filter ...")
 			return NoOpHttpFilter
 		}
 
-		return &httpFilterImpl{manager}
+		return &httpFilterImpl{srv: manager}
 	}
 }
 
-func buildHttpFilterManager(c Context, filterFactoryFunc HttpFilterFactoryFunc) (*httpFilterManager, error) {
+func buildHttpFilterManager(c Context, newFilter filterBuilder) (*httpFilterManager, error) {
 	manager := newHttpFilterManager(c)
 
-	newFilter := filterFactoryFunc()
+	filter, err := newFilter(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP filter, %w", err)
+	}
 
-	if err := newFilter.OnBegin(c, manager); err != nil {
+	if err := filter.OnBegin(c, manager); err != nil {
 		return nil, fmt.Errorf("failed to start HTTP filter, %w", err)
 	}
 
-	manager.completer = func() { httpFilterOnComplete(c, newFilter) }
+	if manager.err != nil {
+		return nil, fmt.Errorf("failed to start HTTP filter, %w", manager.err)
+	}
+
+	manager.completer = func() { httpFilterOnComplete(c, filter) }
 	return manager, nil
 }
 
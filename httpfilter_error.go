@@ -0,0 +1,89 @@
+package gonvoy
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+// HttpFilterErrorHandler is an optional interface that can be implemented by an HttpFilterHandler
+// to handle the errors returned by its own phases, prior to the filter error handling.
+//
+// The errors are resolved in the following order, where the first one handling the error wins:
+// the handler error handler, the error mappings (see HttpFilterController.MapError),
+// and the filter error handler (see HttpFilterController.SetErrorHandler), which defaults to DefaultErrorHandler.
+type HttpFilterErrorHandler interface {
+	// OnError handles the error returned by the handler phases.
+	// It returns false when the error is not handled, hence the error falls through to the filter error handling.
+	//
+	OnError(c Context, err error) (status api.StatusType, handled bool)
+}
+
+// handlerError is an error returned by the phase of a handler implementing HttpFilterErrorHandler.
+type handlerError struct {
+	handler HttpFilterErrorHandler
+	err     error
+}
+
+func (e *handlerError) Error() string { return e.err.Error() }
+func (e *handlerError) Unwrap() error { return e.err }
+
+// errorMapping represents an error that is mapped into a response, see HttpFilterController.MapError.
+type errorMapping struct {
+	target  error
+	code    int
+	body    []byte
+	headers http.Header
+}
+
+func (m *httpFilterManager) MapError(target error, code int, body []byte, headers http.Header) {
+	if target == nil {
+		return
+	}
+
+	m.errorMappings = append(m.errorMappings, errorMapping{
+		target:  target,
+		code:    code,
+		body:    body,
+		headers: headers,
+	})
+}
+
+// handleError resolves the error through the handler error handler, the error mappings, and the filter error handler, in order.
+func (m *httpFilterManager) handleError(c Context, err error) api.StatusType {
+	var herr *handlerError
+	if errors.As(err, &herr) {
+		if status, handled := herr.handler.OnError(c, herr.err); handled {
+			return status
+		}
+
+		err = herr.err
+	}
+
+	for _, mapping := range m.errorMappings {
+		if errors.Is(err, mapping.target) {
+			return mapping.reply(c, err)
+		}
+	}
+
+	return m.errorHandler(c, err)
+}
+
+func (mapping errorMapping) reply(c Context, err error) api.StatusType {
+	c.Log().V(1).Info("request failed with a mapped error", "code", mapping.code, "reason", err.Error())
+
+	err = sendHTTPError(c, &HTTPError{
+		StatusCode:          mapping.code,
+		Headers:             mapping.headers,
+		Body:                mapping.body,
+		ResponseCodeDetails: DefaultResponseCodeDetailError.Wrap(err.Error()),
+		Cause:               err,
+	})
+	if err != nil {
+		c.Log().Error(err, "unexpected error")
+		return api.Continue
+	}
+
+	return api.LocalReply
+}
@@ -0,0 +1,190 @@
+package gonvoy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var errQuotaExceeded = errors.New("quota exceeded")
+
+type fakeErrorHandler struct {
+	PassthroughHttpFilterHandler
+
+	err     error
+	status  api.StatusType
+	handled bool
+	handle  *[]error
+}
+
+func (h fakeErrorHandler) OnRequestHeader(c Context) error { return h.err }
+
+func (h fakeErrorHandler) OnError(c Context, err error) (api.StatusType, bool) {
+	*h.handle = append(*h.handle, err)
+	return h.status, h.handled
+}
+
+func TestHttpFilterManager_ErrorHandling(t *testing.T) {
+	serveRequestHeader := func(mgr *httpFilterManager) api.StatusType {
+		return mgr.ServeDecodeFilter(fakeDecodeHeadersPhase()).Status
+	}
+
+	t.Run("a mapped error is sent as a JSON response", func(t *testing.T) {
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().Log().Return(logr.Discard())
+		mockContext.EXPECT().JSON(http.StatusTooManyRequests, []byte(`{"code":"QUOTA_EXCEEDED"}`), mock.Anything, mock.Anything).
+			RunAndReturn(func(code int, body []byte, opts ...LocalReplyOption) error {
+				reply := NewLocalReplyOptions(opts...)
+				assert.Equal(t, "60", reply.headers.Get("Retry-After"))
+				assert.Equal(t, "gateway", reply.headers.Get("reporter"))
+				return nil
+			})
+
+		mgr := newHttpFilterManager(mockContext)
+		mgr.MapError(nil, http.StatusTeapot, nil, nil)
+		mgr.MapError(errQuotaExceeded, http.StatusTooManyRequests, []byte(`{"code":"QUOTA_EXCEEDED"}`), http.Header{"Retry-After": {"60"}})
+		mgr.MapError(ErrBadRequest, http.StatusBadRequest, nil, nil)
+		mgr.AddHandler(fakeRecordHandler{name: "first", calls: new([]string), err: fmt.Errorf("user foo, %w", errQuotaExceeded)})
+
+		assert.Len(t, mgr.errorMappings, 2)
+		assert.Equal(t, api.LocalReply, serveRequestHeader(mgr))
+	})
+
+	t.Run("a mapped error with content type is sent as it is", func(t *testing.T) {
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().Log().Return(logr.Discard())
+		mockContext.EXPECT().SendResponse(http.StatusTooManyRequests, "quota exceeded", mock.Anything, mock.Anything).Return(nil)
+
+		mgr := newHttpFilterManager(mockContext)
+		mgr.MapError(errQuotaExceeded, http.StatusTooManyRequests, []byte("quota exceeded"), http.Header{HeaderContentType: {MIMETextPlainCharsetUTF8}})
+		mgr.AddHandler(fakeRecordHandler{name: "first", calls: new([]string), err: errQuotaExceeded})
+
+		assert.Equal(t, api.LocalReply, serveRequestHeader(mgr))
+	})
+
+	t.Run("the handler error handler takes precedence", func(t *testing.T) {
+		var handled []error
+
+		mockContext := NewMockContext(t)
+
+		mgr := newHttpFilterManager(mockContext)
+		mgr.MapError(errQuotaExceeded, http.StatusTooManyRequests, nil, nil)
+		mgr.AddHandler(fakeErrorHandler{err: errQuotaExceeded, status: api.Continue, handled: true, handle: &handled})
+
+		assert.Equal(t, api.Continue, serveRequestHeader(mgr))
+		assert.Equal(t, []error{errQuotaExceeded}, handled)
+	})
+
+	t.Run("an unhandled error falls through the error mappings and the filter error handler", func(t *testing.T) {
+		var handled []error
+		var filterErrors []error
+
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().Committed().Return(false)
+
+		mgr := newHttpFilterManager(mockContext)
+		mgr.SetErrorHandler(func(c Context, err error) api.StatusType {
+			filterErrors = append(filterErrors, err)
+			return api.LocalReply
+		})
+		mgr.MapError(errQuotaExceeded, http.StatusTooManyRequests, nil, nil)
+		mgr.AddHandler(PassthroughHttpFilterHandler{})
+		mgr.AddHandler(fakeErrorHandler{err: ErrAccessDenied, handle: &handled})
+
+		assert.Equal(t, api.LocalReply, serveRequestHeader(mgr))
+		assert.Equal(t, []error{ErrAccessDenied}, handled)
+		assert.Equal(t, []error{ErrAccessDenied}, filterErrors, "the filter error handler should receive the original error")
+	})
+}
+
+func TestDefaultErrorHandler(t *testing.T) {
+	newErrorContext := func(t *testing.T) *MockContext {
+		c := NewMockContext(t)
+		c.EXPECT().Log().Return(logr.Discard())
+		c.EXPECT().GetProperty(mock.Anything, mock.Anything).Return("-", nil)
+		c.EXPECT().ErrorRenderer().Return(nil).Maybe()
+		return c
+	}
+
+	t.Run("HTTPError is rendered with its details", func(t *testing.T) {
+		httpErr := &HTTPError{
+			StatusCode:          http.StatusTooManyRequests,
+			Code:                "QUOTA_EXCEEDED",
+			Message:             "Quota Exceeded",
+			Headers:             http.Header{"Retry-After": {"60"}},
+			ResponseCodeDetails: "quota_exceeded",
+			GRPCStatus:          8,
+			Cause:               errQuotaExceeded,
+		}
+
+		c := newErrorContext(t)
+		c.EXPECT().JSON(http.StatusTooManyRequests, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			RunAndReturn(func(code int, body []byte, opts ...LocalReplyOption) error {
+				assert.Contains(t, string(body), `"code":"QUOTA_EXCEEDED"`)
+				assert.Contains(t, string(body), `"message":"Quota Exceeded"`)
+				assert.NotContains(t, string(body), errQuotaExceeded.Error(), "the cause shouldn't be exposed")
+
+				reply := NewLocalReplyOptions(opts...)
+				assert.Equal(t, "60", reply.headers.Get("Retry-After"))
+				assert.Equal(t, "quota_exceeded", reply.responseCodeDetails)
+				assert.Equal(t, int64(8), reply.grpcStatusCode)
+				return nil
+			})
+
+		assert.Equal(t, api.LocalReply, DefaultErrorHandler(c, fmt.Errorf("wrapped, %w", httpErr)))
+	})
+
+	t.Run("HTTPError with a custom body and content type", func(t *testing.T) {
+		c := newErrorContext(t)
+		c.EXPECT().SendResponse(http.StatusConflict, "conflict", mock.Anything, mock.Anything).Return(nil)
+
+		assert.Equal(t, api.LocalReply, DefaultErrorHandler(c, &HTTPError{
+			StatusCode: http.StatusConflict,
+			Headers:    http.Header{HeaderContentType: {MIMETextPlainCharsetUTF8}},
+			Body:       []byte("conflict"),
+		}))
+	})
+
+	t.Run("sentinel errors are mapped to their status code", func(t *testing.T) {
+		testcases := []struct {
+			err          error
+			expectedCode int
+		}{
+			{err: ErrBadRequest, expectedCode: http.StatusBadRequest},
+			{err: ErrInternalServer, expectedCode: http.StatusInternalServerError},
+			{err: ErrUnauthorized, expectedCode: http.StatusUnauthorized},
+			{err: ErrAccessDenied, expectedCode: http.StatusForbidden},
+		}
+
+		for _, tc := range testcases {
+			t.Run(tc.err.Error(), func(t *testing.T) {
+				c := newErrorContext(t)
+				c.EXPECT().JSON(tc.expectedCode, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+				assert.Equal(t, api.LocalReply, DefaultErrorHandler(c, fmt.Errorf("invalid payload, %w", tc.err)))
+			})
+		}
+	})
+}
+
+func TestHTTPError(t *testing.T) {
+	httpErr := &HTTPError{StatusCode: http.StatusTooManyRequests, Cause: errQuotaExceeded}
+	assert.Equal(t, "TOO_MANY_REQUESTS", httpErr.code())
+	assert.Equal(t, "Too Many Requests, quota exceeded", httpErr.Error())
+	assert.ErrorIs(t, httpErr, errQuotaExceeded)
+
+	httpErr = NewHTTPError(0, "", "")
+	assert.Equal(t, http.StatusInternalServerError, httpErr.statusCode())
+	assert.Equal(t, "INTERNAL_SERVER_ERROR", httpErr.code())
+	assert.Equal(t, "Internal Server Error", httpErr.Error())
+
+	httpErr = NewHTTPError(499, "", "")
+	assert.Equal(t, "ERROR", httpErr.code())
+	assert.Equal(t, "Error", httpErr.message())
+}
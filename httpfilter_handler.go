@@ -2,6 +2,7 @@ package gonvoy
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
@@ -34,18 +35,58 @@ type HttpFilterHandler interface {
 	OnResponseBody(c Context) error
 }
 
-var (
-	responseUnauthorized        = NewMinimalJSONResponse("UNAUTHORIZED", "Unauthorized")
-	responseForbidden           = NewMinimalJSONResponse("FORBIDDEN", "Forbidden")
-	responseClientClosedRequest = NewMinimalJSONResponse("CLIENT_CLOSED_REQUEST", "Client Closed Request")
-	responseRuntimeError        = NewMinimalJSONResponse("RUNTIME_ERROR", "Runtime Error")
-	responseBadGateway          = NewMinimalJSONResponse("BAD_GATEWAY", "Bad Gateway")
-)
+// HttpFilterTrailerHandler is an optional interface that can be implemented by an HttpFilterHandler
+// to process the HTTP request and/or response trailers, e.g., grpc-status, grpc-message, or checksums on chunked transfers.
+// In a typical HTTP flow, the trailer phases are the last phase of each direction:
+// OnRequestHeader -> OnRequestBody -> OnRequestTrailer -> ... -> OnResponseHeader -> OnResponseBody -> OnResponseTrailer
+//
+// Note that trailer phases are only called when the HTTP request or response carries trailers.
+type HttpFilterTrailerHandler interface {
+	// OnRequestTrailer is called when processing the HTTP request trailer during the OnRequestTrailer phase.
+	//
+	OnRequestTrailer(c Context) error
+
+	// OnResponseTrailer is called when processing the HTTP response trailer during the OnResponseTrailer phase.
+	//
+	OnResponseTrailer(c Context) error
+}
+
+// HttpFilterChunkHandler is an optional interface that can be implemented by an HttpFilterHandler
+// to process the HTTP request and/or response body chunk by chunk as it arrives, instead of the entirely buffered body.
+// The chunk phases are only called when the body streaming is enabled, see EnableRequestBodyStreaming and EnableResponseBodyStreaming options.
+//
+// A chunk can be modified through its Write or WriteString method, given the body is writable. Otherwise, the chunk is passed through as it is.
+type HttpFilterChunkHandler interface {
+	// OnRequestChunk is called for every HTTP request body chunk during the OnRequestChunk phase.
+	// The endStream indicates whether the chunk is the last one.
+	//
+	OnRequestChunk(c Context, chunk Body, endStream bool) error
+
+	// OnResponseChunk is called for every HTTP response body chunk during the OnResponseChunk phase.
+	// The endStream indicates whether the chunk is the last one.
+	//
+	OnResponseChunk(c Context, chunk Body, endStream bool) error
+}
+
+// HttpFilterAsyncHandler is an optional interface that can be implemented by an HttpFilterHandler
+// to run its phases asynchronously, off the Envoy worker thread.
+// It is intended for handlers that perform blocking operations, such as token introspection or database lookups.
+//
+// Once an asynchronous handler is registered, every phase of the filter is executed within a goroutine,
+// the filter returns a Running status to Envoy, and the filter chain is resumed when the phase has finished.
+type HttpFilterAsyncHandler interface {
+	HttpFilterHandler
+
+	// Async reports whether the HTTP filter handler should be executed asynchronously.
+	//
+	Async() bool
+}
 
 // ErrorHandler is a function type that handles errors in the HTTP filter.
 type ErrorHandler func(Context, error) api.StatusType
 
 // DefaultErrorHandler is a default error handler if no custom error handler is provided.
+// The error responses are rendered with the configured ErrorRenderer, see ConfigOptions.ErrorRenderer.
 func DefaultErrorHandler(c Context, err error) api.StatusType {
 	if err == nil {
 		return api.Continue
@@ -56,35 +97,53 @@ func DefaultErrorHandler(c Context, err error) api.StatusType {
 	path := MustGetProperty(c, "request.path", "-")
 	log := c.Log().WithValues("host", host, "method", method, "path", path)
 
+	var httpErr *HTTPError
+
 	switch {
+	case errors.As(err, &httpErr):
+		if httpErr.statusCode() >= http.StatusInternalServerError {
+			log.Error(err, "request failed")
+		} else {
+			log.V(1).Info("request failed", "code", httpErr.statusCode(), "reason", err.Error())
+		}
+
+		err = sendHTTPError(c, httpErr)
+
+	case errors.Is(err, ErrBadRequest):
+		log.V(1).Info("bad request", "reason", err.Error())
+
+		err = sendHTTPError(c, newSentinelHTTPError(http.StatusBadRequest, "BAD_REQUEST", "Bad Request",
+			DefaultResponseCodeDetailInfo.Wrap(err.Error())))
+
+	case errors.Is(err, ErrInternalServer):
+		log.Error(err, "internal server error")
+
+		err = sendHTTPError(c, newSentinelHTTPError(http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Internal Server Error",
+			DefaultResponseCodeDetailError.Wrap(err.Error())))
+
 	case errors.Is(err, ErrUnauthorized):
 		log.V(1).Info("request unauthorized", "reason", err.Error())
 
-		err = c.JSON(http.StatusUnauthorized, responseUnauthorized,
-			LocalReplyWithHTTPHeaders(NewGatewayHeaders()),
-			LocalReplyWithRCDetails(DefaultResponseCodeDetailUnauthorized.Wrap(err.Error())))
+		err = sendHTTPError(c, newSentinelHTTPError(http.StatusUnauthorized, "UNAUTHORIZED", "Unauthorized",
+			DefaultResponseCodeDetailUnauthorized.Wrap(err.Error())))
 
 	case errors.Is(err, ErrAccessDenied):
 		log.V(1).Info("request denied", "reason", err.Error())
 
-		err = c.JSON(http.StatusForbidden, responseForbidden,
-			LocalReplyWithHTTPHeaders(NewGatewayHeaders()),
-			LocalReplyWithRCDetails(DefaultResponseCodeDetailAccessDenied.Wrap(err.Error())))
+		err = sendHTTPError(c, newSentinelHTTPError(http.StatusForbidden, "FORBIDDEN", "Forbidden",
+			DefaultResponseCodeDetailAccessDenied.Wrap(err.Error())))
 
 	case errors.Is(err, ErrOperationNotPermitted):
 		log.V(1).Info("request operation not permitted", "reason", err.Error())
 
-		err = c.JSON(http.StatusBadGateway, responseBadGateway,
-			LocalReplyWithHTTPHeaders(NewGatewayHeaders()),
-			LocalReplyWithRCDetails(DefaultResponseCodeDetailError.Wrap(err.Error())))
+		err = sendHTTPError(c, newSentinelHTTPError(http.StatusBadGateway, "BAD_GATEWAY", "Bad Gateway",
+			DefaultResponseCodeDetailError.Wrap(err.Error())))
 
 	case errors.Is(err, ErrClientClosedRequest):
 		log.V(1).Info("request prematurely closed by client", "reason", err.Error())
 
-		err = c.JSON(499, responseClientClosedRequest,
-			LocalReplyWithHTTPHeaders(NewGatewayHeaders()),
-			LocalReplyWithRCDetails(DefaultResponseCodeDetailInfo.Wrap(err.Error())),
-		)
+		err = sendHTTPError(c, newSentinelHTTPError(499, "CLIENT_CLOSED_REQUEST", "Client Closed Request",
+			DefaultResponseCodeDetailInfo.Wrap(err.Error())))
 
 	default:
 		log := c.Log().WithCallDepth(3)
@@ -96,9 +155,8 @@ func DefaultErrorHandler(c Context, err error) api.StatusType {
 		// but printed out the error details to envoy log
 		log.Error(err, "unidentified error")
 
-		err = c.JSON(http.StatusInternalServerError, responseRuntimeError,
-			LocalReplyWithHTTPHeaders(NewGatewayHeaders()),
-			LocalReplyWithRCDetails(DefaultResponseCodeDetailError.Wrap(err.Error())))
+		err = sendHTTPError(c, newSentinelHTTPError(http.StatusInternalServerError, "RUNTIME_ERROR", "Runtime Error",
+			DefaultResponseCodeDetailError.Wrap(err.Error())))
 	}
 
 	if err != nil {
@@ -113,12 +171,100 @@ func DefaultErrorHandler(c Context, err error) api.StatusType {
 	return api.LocalReply
 }
 
-var _ HttpFilterHandler = PassthroughHttpFilterHandler{}
+func newSentinelHTTPError(statusCode int, code, message, rcDetails string) *HTTPError {
+	return &HTTPError{
+		StatusCode:          statusCode,
+		Code:                code,
+		Message:             message,
+		ResponseCodeDetails: rcDetails,
+	}
+}
+
+// sendHTTPError sends the HTTPError as a local reply, where the body is rendered with the configured ErrorRenderer, unless HTTPError.Body is set.
+// The gRPC requests are replied with the gRPC status instead, see sendGRPCError.
+func sendHTTPError(c Context, httpErr *HTTPError) error {
+	rcDetails := httpErr.ResponseCodeDetails
+	if rcDetails == "" {
+		rcDetails = DefaultResponseCodeDetailError.Wrap(httpErr.Error())
+	}
+
+	if isGRPCRequest(c) {
+		return sendGRPCError(c, httpErr, LocalReplyWithRCDetails(rcDetails))
+	}
+
+	headers := make(http.Header, len(httpErr.Headers)+1)
+	for key, values := range httpErr.Headers {
+		headers[key] = values
+	}
+
+	body := httpErr.Body
+	if body == nil {
+		renderer := c.ErrorRenderer()
+		if renderer == nil {
+			renderer = DefaultErrorRenderer
+		}
+
+		var (
+			contentType string
+			err         error
+		)
+
+		body, contentType, err = renderer.Render(c, httpErr)
+		if err != nil {
+			return fmt.Errorf("failed to render error response, %w", err)
+		}
+
+		if headers.Get(HeaderContentType) == "" && contentType != "" {
+			headers.Set(HeaderContentType, contentType)
+		}
+
+		if _, ok := renderer.(negotiatedErrorRenderer); ok {
+			headers.Add(HeaderVary, HeaderAccept)
+		}
+	}
+
+	opts := []LocalReplyOption{LocalReplyWithRCDetails(rcDetails)}
+	if httpErr.GRPCStatus > 0 {
+		opts = append(opts, LocalReplyWithGRPCStatus(httpErr.GRPCStatus))
+	}
+
+	return sendErrorResponse(c, httpErr.statusCode(), body, headers, opts...)
+}
+
+// sendErrorResponse sends the error response along with the gateway headers,
+// where the body is sent as a JSON response, unless the headers carry another Content-Type.
+func sendErrorResponse(c Context, code int, body []byte, headers http.Header, opts ...LocalReplyOption) error {
+	replyHeaders := NewGatewayHeaders()
+	for key, values := range headers {
+		replyHeaders[key] = values
+	}
+
+	opts = append(opts, LocalReplyWithHTTPHeaders(replyHeaders))
+	if contentType := replyHeaders.Get(HeaderContentType); contentType != "" && contentType != MIMEApplicationJSON {
+		return c.SendResponse(code, string(body), opts...)
+	}
+
+	return c.JSON(code, body, opts...)
+}
+
+var (
+	_ HttpFilterHandler        = PassthroughHttpFilterHandler{}
+	_ HttpFilterTrailerHandler = PassthroughHttpFilterHandler{}
+	_ HttpFilterChunkHandler   = PassthroughHttpFilterHandler{}
+)
 
 type PassthroughHttpFilterHandler struct{}
 
-func (PassthroughHttpFilterHandler) Disable() bool                    { return false }
-func (PassthroughHttpFilterHandler) OnRequestHeader(c Context) error  { return nil }
-func (PassthroughHttpFilterHandler) OnRequestBody(c Context) error    { return nil }
-func (PassthroughHttpFilterHandler) OnResponseHeader(c Context) error { return nil }
-func (PassthroughHttpFilterHandler) OnResponseBody(c Context) error   { return nil }
+func (PassthroughHttpFilterHandler) Disable() bool                     { return false }
+func (PassthroughHttpFilterHandler) OnRequestHeader(c Context) error   { return nil }
+func (PassthroughHttpFilterHandler) OnRequestBody(c Context) error     { return nil }
+func (PassthroughHttpFilterHandler) OnResponseHeader(c Context) error  { return nil }
+func (PassthroughHttpFilterHandler) OnResponseBody(c Context) error    { return nil }
+func (PassthroughHttpFilterHandler) OnRequestTrailer(c Context) error  { return nil }
+func (PassthroughHttpFilterHandler) OnResponseTrailer(c Context) error { return nil }
+func (PassthroughHttpFilterHandler) OnRequestChunk(c Context, chunk Body, endStream bool) error {
+	return nil
+}
+func (PassthroughHttpFilterHandler) OnResponseChunk(c Context, chunk Body, endStream bool) error {
+	return nil
+}
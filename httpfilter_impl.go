@@ -9,6 +9,11 @@ var _ api.StreamFilter = &httpFilterImpl{}
 // httpFilterImpl is an HTTP Filter implementation for Envoy.
 type httpFilterImpl struct {
 	srv HttpFilterServer
+
+	// requestBodyPending and responseBodyPending report whether the body is being buffered, yet it hasn't been handled,
+	// see flushRequestBody and flushResponseBody.
+	requestBodyPending  bool
+	responseBodyPending bool
 }
 
 func (f *httpFilterImpl) OnLog() { f.srv.Complete() }
@@ -16,6 +21,10 @@ func (f *httpFilterImpl) OnLog() { f.srv.Complete() }
 func (f *httpFilterImpl) OnDestroy(reason api.DestroyReason) { f.srv = nil }
 
 func (f *httpFilterImpl) DecodeHeaders(header api.RequestHeaderMap, endStream bool) api.StatusType {
+	if router, ok := f.srv.(httpFilterRouter); ok {
+		router.route(header.Method(), header.Path())
+	}
+
 	result := f.srv.ServeDecodeFilter(f.handleRequestHeader(header))
 	return result.Status
 }
@@ -35,6 +44,16 @@ func (f *httpFilterImpl) EncodeData(buffer api.BufferInstance, endStream bool) a
 	return result.Status
 }
 
+func (f *httpFilterImpl) DecodeTrailers(trailer api.RequestTrailerMap) api.StatusType {
+	result := f.srv.ServeDecodeFilter(f.handleRequestTrailer(trailer))
+	return result.Status
+}
+
+func (f *httpFilterImpl) EncodeTrailers(trailer api.ResponseTrailerMap) api.StatusType {
+	result := f.srv.ServeEncodeFilter(f.handleResponseTrailer(trailer))
+	return result.Status
+}
+
 func (f *httpFilterImpl) handleRequestHeader(header api.RequestHeaderMap) HttpFilterDecoderFunc {
 	return func(c Context, p HttpFilterDecodeProcessor) (HttpFilterAction, error) {
 		c.LoadRequestHeaders(header)
@@ -43,6 +62,16 @@ func (f *httpFilterImpl) handleRequestHeader(header api.RequestHeaderMap) HttpFi
 			return ActionContinue, err
 		}
 
+		if c.IsRequestBodyStreaming() {
+			// In streaming mode, the request headers are never buffered, as the request body is processed chunk by chunk.
+			// Since the chunks might be modified, the content length is always removed.
+			if c.IsRequestBodyWritable() {
+				_ = deleteContentLength(header)
+			}
+
+			return ActionContinue, nil
+		}
+
 		if c.IsRequestBodyWritable() {
 			// If content length is omitted, there's no need for the filter manager to buffer the request headers.
 			// Therefore, we can continue the flow.
@@ -63,14 +92,21 @@ func (f *httpFilterImpl) handleRequestBody(buffer api.BufferInstance, endStream
 			return ActionSkip, nil
 		}
 
+		if c.IsRequestBodyStreaming() {
+			chunk := newChunkBody(buffer, c.IsRequestBodyWritable())
+			return ActionContinue, p.HandleOnRequestChunk(c, chunk, endStream)
+		}
+
 		c.LoadRequestBody(buffer, endStream)
 
 		if !endStream {
 			// Wait -- we'll be called again when the complete body is buffered
 			// at the Envoy host side.
+			f.requestBodyPending = true
 			return ActionWait, nil
 		}
 
+		f.requestBodyPending = false
 		return ActionContinue, p.HandleOnRequestBody(c)
 	}
 }
@@ -83,6 +119,16 @@ func (f *httpFilterImpl) handleResponseHeader(header api.ResponseHeaderMap) Http
 			return ActionContinue, err
 		}
 
+		if c.IsResponseBodyStreaming() {
+			// In streaming mode, the response headers are sent to the downstream right away,
+			// hence the filter is no longer able to interrupt it with a custom error response during the response body processing.
+			if c.IsResponseBodyWritable() {
+				_ = deleteContentLength(header)
+			}
+
+			return ActionContinue, nil
+		}
+
 		// During the Encode phases or HTTP Response flows,
 		// if a user needs access to the HTTP Response Body, whether for reading or writing,
 		// the EncodeHeaders phase should return with ActionPause (StopAndBuffer status) action.
@@ -119,19 +165,93 @@ func (f *httpFilterImpl) handleResponseBody(buffer api.BufferInstance, endStream
 			return ActionSkip, nil
 		}
 
+		if c.IsResponseBodyStreaming() {
+			chunk := newChunkBody(buffer, c.IsResponseBodyWritable())
+			return ActionContinue, p.HandleOnResponseChunk(c, chunk, endStream)
+		}
+
 		c.LoadResponseBody(buffer, endStream)
 
 		if !endStream {
 			// Wait -- we'll be called again when the complete body is buffered
 			// at the Envoy host side.
+			f.responseBodyPending = true
 			return ActionWait, nil
 		}
 
+		f.responseBodyPending = false
 		return ActionContinue, p.HandleOnResponseBody(c)
 	}
 }
 
-func (*httpFilterImpl) DecodeTrailers(api.RequestTrailerMap) api.StatusType  { return api.Continue }
-func (*httpFilterImpl) EncodeTrailers(api.ResponseTrailerMap) api.StatusType { return api.Continue }
-func (*httpFilterImpl) OnLogDownstreamPeriodic()                             {}
-func (*httpFilterImpl) OnLogDownstreamStart()                                {}
+// handleRequestTrailer handles the HTTP request trailers.
+// Note that Envoy only accepts Continue or LocalReply status during the trailer phases,
+// hence the trailer phases never pause nor wait.
+func (f *httpFilterImpl) handleRequestTrailer(trailer api.RequestTrailerMap) HttpFilterDecoderFunc {
+	return func(c Context, p HttpFilterDecodeProcessor) (HttpFilterAction, error) {
+		if c.Committed() {
+			// The previous phase has been committed, e.g. through SkipNextPhase, hence the trailer phase is skipped.
+			return ActionSkip, nil
+		}
+
+		if err := f.flushRequestBody(c, p); err != nil {
+			return ActionContinue, err
+		}
+
+		if c.Committed() {
+			return ActionSkip, nil
+		}
+
+		c.LoadRequestTrailers(trailer)
+
+		return ActionContinue, p.HandleOnRequestTrailer(c)
+	}
+}
+
+func (f *httpFilterImpl) handleResponseTrailer(trailer api.ResponseTrailerMap) HttpFilterEncoderFunc {
+	return func(c Context, p HttpFilterEncodeProcessor) (HttpFilterAction, error) {
+		if c.Committed() {
+			// ditto
+			return ActionSkip, nil
+		}
+
+		if err := f.flushResponseBody(c, p); err != nil {
+			return ActionContinue, err
+		}
+
+		if c.Committed() {
+			return ActionSkip, nil
+		}
+
+		c.LoadResponseTrailers(trailer)
+
+		return ActionContinue, p.HandleOnResponseTrailer(c)
+	}
+}
+
+// flushRequestBody handles the pending request body prior to the request trailer phase.
+// While the body is being buffered, Envoy calls the trailer phase right away, without the end of stream data phase,
+// hence the body buffered so far is the complete body. Since the Envoy buffer is no longer available, the body is read-only.
+func (f *httpFilterImpl) flushRequestBody(c Context, p HttpFilterDecodeProcessor) error {
+	if !f.requestBodyPending {
+		return nil
+	}
+
+	f.requestBodyPending = false
+	c.LoadRequestBody(detachedBuffer{}, true)
+	return p.HandleOnRequestBody(c)
+}
+
+// flushResponseBody handles the pending response body prior to the response trailer phase, see flushRequestBody.
+func (f *httpFilterImpl) flushResponseBody(c Context, p HttpFilterEncodeProcessor) error {
+	if !f.responseBodyPending {
+		return nil
+	}
+
+	f.responseBodyPending = false
+	c.LoadResponseBody(detachedBuffer{}, true)
+	return p.HandleOnResponseBody(c)
+}
+
+func (*httpFilterImpl) OnLogDownstreamPeriodic() {}
+func (*httpFilterImpl) OnLogDownstreamStart()    {}
@@ -1,7 +1,9 @@
 package gonvoy
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/ardikabs/gonvoy/pkg/util"
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
@@ -53,11 +55,31 @@ type HttpFilterController interface {
 	//
 	SetErrorHandler(handler ErrorHandler)
 
+	// MapError maps the error into a response with the status code, body, and headers,
+	// where the error matches the target through errors.Is, including the errors wrapping the target.
+	// The body is sent as a JSON response, unless the headers carry a Content-Type.
+	// When the body is nil, the response is rendered with the configured ErrorRenderer, see ConfigOptions.ErrorRenderer.
+	//
+	// The error mappings are evaluated in the registration order, after the handler error handler (see HttpFilterErrorHandler)
+	// and before the filter error handler, hence the domain errors can be mapped without re-implementing the DefaultErrorHandler.
+	//
+	// Example usage:
+	//
+	//	func (f *UserFilter) OnBegin(c RuntimeContext, ctrl HttpFilterController) error {
+	//		...
+	//		ctrl.MapError(ErrQuotaExceeded, http.StatusTooManyRequests, NewMinimalJSONResponse("QUOTA_EXCEEDED", "Quota Exceeded"), http.Header{"Retry-After": {"60"}})
+	//	}
+	//
+	MapError(target error, code int, body []byte, headers http.Header)
+
 	// AddHandler adds an HTTP Filter Handler to the controller,
 	// which should be run during filter startup (HttpFilter.OnBegin).
 	// It's important to note the order when adding filter handlers.
 	// While HTTP requests follow FIFO sequences, HTTP responses follow LIFO sequences.
 	//
+	// When the handler implements HttpFilterAsyncHandler and reports itself as asynchronous,
+	// every phase of the filter will be executed asynchronously.
+	//
 	// Example usage:
 	//
 	//	func (f *UserFilter) OnBegin(c RuntimeContext, ctrl HttpFilterController) error {
@@ -72,6 +94,63 @@ type HttpFilterController interface {
 	// During HTTP responses, traffic flows in reverse: `handlerD -> handlerC -> handlerB -> handlerA`.
 	//
 	AddHandler(handler HttpFilterHandler)
+
+	// AddHandlerWhen is similar to AddHandler, except the handler only runs for requests matching the predicate.
+	// The predicate is evaluated once during the OnRequestHeader phase, right before the handler is called.
+	// Once the predicate doesn't match, every phase of the handler is skipped for the request, including the body and response phases,
+	// while the handler chain proceeds to the next handlers.
+	// A nil predicate always matches.
+	//
+	// Example usage:
+	//
+	//	func (f *UserFilter) OnBegin(c RuntimeContext, ctrl HttpFilterController) error {
+	//		...
+	//		ctrl.AddHandlerWhen(And(MethodIs(http.MethodPost), ContentTypeIs(MIMEApplicationJSON)), handlerA)
+	//		ctrl.AddHandlerWhen(Or(PathPrefix("/admin"), HostMatches("admin.*")), handlerB)
+	//	}
+	//
+	AddHandlerWhen(predicate HttpFilterPredicate, handler HttpFilterHandler)
+
+	// Route adds HTTP Filter Handlers to the controller, which only run for requests matching the route pattern.
+	// The pattern is a path, optionally preceded by a method and a space, e.g., `POST /v1/orders/{id}`.
+	// A `{name}` segment matches a single path segment, while a `{name...}` segment, which must be the last, matches the remaining path.
+	// The matched values are available through Context.PathParam.
+	//
+	// The request is matched once, right before the OnRequestHeader phase, and only the first matching route joins the handler chain,
+	// along with the handlers added through AddHandler, following the registration order.
+	// The same FIFO and LIFO sequences as AddHandler apply to the request and response phases.
+	//
+	// Example usage:
+	//
+	//	func (f *UserFilter) OnBegin(c RuntimeContext, ctrl HttpFilterController) error {
+	//		...
+	//		ctrl.AddHandler(handlerA)
+	//		ctrl.Route("POST /v1/orders/{id}", handlerB, handlerC)
+	//		ctrl.Route("/v1/orders/{id}", handlerD)
+	//	}
+	//
+	// A `POST /v1/orders/1` request flows through `handlerA -> handlerB -> handlerC`,
+	// while a `GET /v1/orders/1` request flows through `handlerA -> handlerD`.
+	//
+	// An invalid pattern fails the filter startup, see HttpFilter.OnBegin.
+	//
+	Route(pattern string, handlers ...HttpFilterHandler)
+
+	// Use adds middlewares that wrap every phase call of the HTTP filter handlers, on both the request and response phases.
+	// The middlewares apply to every handler regardless of the registration order,
+	// where the first added middleware is the outermost one.
+	//
+	// Example usage:
+	//
+	//	func (f *UserFilter) OnBegin(c RuntimeContext, ctrl HttpFilterController) error {
+	//		...
+	//		ctrl.Use(timing, logging)
+	//		ctrl.AddHandler(handlerA)
+	//	}
+	//
+	// During the OnRequestHeader phase of handlerA, the call flows through `timing -> logging -> handlerA.OnRequestHeader`.
+	//
+	Use(middlewares ...HttpFilterMiddleware)
 }
 
 // HttpFilterServer is an HTTP filter server used for handling HTTP requests and responses.
@@ -79,13 +158,19 @@ type HttpFilterController interface {
 type HttpFilterServer interface {
 	// ServeDecodeFilter serves decode phase of an HTTP filter.
 	// This method is designed for internal use as it is used during the decoding phase only.
-	// Decode phase is when the filter processes the incoming request, which consist of processing headers and body.
+	// Decode phase is when the filter processes the incoming request, which consist of processing headers, body, and trailers.
+	//
+	// When the filter runs asynchronously, it immediately returns a result with Running status,
+	// and the actual result is delivered to Envoy once the phase has finished.
 	//
 	ServeDecodeFilter(HttpFilterDecoderFunc) *HttpFilterResult
 
 	// ServeEncodeFilter serves encode phase of an HTTP filter.
 	// This method is designed for internal use as it is used during the encoding phase only.
-	// Encode phase is when the filter processes the upstream response, which consist of processing headers and body.
+	// Encode phase is when the filter processes the upstream response, which consist of processing headers, body, and trailers.
+	//
+	// When the filter runs asynchronously, it immediately returns a result with Running status,
+	// and the actual result is delivered to Envoy once the phase has finished.
 	//
 	ServeEncodeFilter(HttpFilterEncoderFunc) *HttpFilterResult
 
@@ -112,6 +197,20 @@ type httpFilterManager struct {
 	first        HttpFilterProcessor
 	last         HttpFilterProcessor
 	completer    HttpFilterCompletionFunc
+	async        bool
+
+	entries       []httpFilterEntry
+	middlewares   []HttpFilterMiddleware
+	errorMappings []errorMapping
+	err           error
+}
+
+// httpFilterEntry represents the handlers registered to the manager,
+// which join the handler chain when the route matches, or unconditionally when no route is given.
+type httpFilterEntry struct {
+	route    *httpFilterRoute
+	when     HttpFilterPredicate
+	handlers []HttpFilterHandler
 }
 
 func (m *httpFilterManager) SetErrorHandler(handler ErrorHandler) {
@@ -123,11 +222,91 @@ func (m *httpFilterManager) SetErrorHandler(handler ErrorHandler) {
 }
 
 func (m *httpFilterManager) AddHandler(handler HttpFilterHandler) {
-	if util.IsNil(handler) || handler.Disable() {
+	m.AddHandlerWhen(nil, handler)
+}
+
+func (m *httpFilterManager) AddHandlerWhen(predicate HttpFilterPredicate, handler HttpFilterHandler) {
+	if !isHandlerEnabled(handler) {
+		return
+	}
+
+	m.entries = append(m.entries, httpFilterEntry{when: predicate, handlers: []HttpFilterHandler{handler}})
+	m.link(handler, predicate)
+}
+
+func (m *httpFilterManager) Route(pattern string, handlers ...HttpFilterHandler) {
+	route, err := parseHttpFilterRoute(pattern)
+	if err != nil {
+		m.err = errors.Join(m.err, fmt.Errorf("invalid route pattern '%s', %w", pattern, err))
+		return
+	}
+
+	entry := httpFilterEntry{route: route}
+	for _, handler := range handlers {
+		if isHandlerEnabled(handler) {
+			entry.handlers = append(entry.handlers, handler)
+		}
+	}
+
+	m.entries = append(m.entries, entry)
+}
+
+func (m *httpFilterManager) Use(middlewares ...HttpFilterMiddleware) {
+	for _, mw := range middlewares {
+		if mw != nil {
+			m.middlewares = append(m.middlewares, mw)
+		}
+	}
+}
+
+// route rebuilds the handler chain with the handlers that match the request, following the registration order.
+func (m *httpFilterManager) route(method, path string) {
+	hasRoute := false
+	for _, entry := range m.entries {
+		if entry.route != nil {
+			hasRoute = true
+			break
+		}
+	}
+
+	if !hasRoute {
 		return
 	}
 
-	proc := newHttpFilterProcessor(handler)
+	m.first, m.last, m.async = nil, nil, false
+
+	matched := false
+	for _, entry := range m.entries {
+		if entry.route != nil {
+			if matched {
+				continue
+			}
+
+			params, ok := entry.route.match(method, path)
+			if !ok {
+				continue
+			}
+
+			matched = true
+			if fCtx, ok := m.ctx.(*context); ok {
+				fCtx.pathParams = params
+			}
+		}
+
+		for _, handler := range entry.handlers {
+			m.link(handler, entry.when)
+		}
+	}
+}
+
+// link appends the handler to the end of the handler chain.
+func (m *httpFilterManager) link(handler HttpFilterHandler, when HttpFilterPredicate) {
+	if asyncHandler, ok := handler.(HttpFilterAsyncHandler); ok && asyncHandler.Async() {
+		m.async = true
+	}
+
+	proc := newHttpFilterProcessor(handler, &m.middlewares)
+	proc.when = when
 	if m.first == nil {
 		m.first = proc
 		m.last = proc
@@ -139,34 +318,69 @@ func (m *httpFilterManager) AddHandler(handler HttpFilterHandler) {
 	m.last = proc
 }
 
-func (m *httpFilterManager) ServeDecodeFilter(fn HttpFilterDecoderFunc) (res *HttpFilterResult) {
+func (m *httpFilterManager) ServeDecodeFilter(fn HttpFilterDecoderFunc) *HttpFilterResult {
+	var pcb api.FilterProcessCallbacks
 	if fCtx, ok := m.ctx.(*context); ok {
 		fCtx.pcb = fCtx.cb.DecoderFilterCallbacks()
+		pcb = fCtx.pcb
 	}
 
-	res = newHttpFilterResult()
-	defer res.Finalize(m.ctx, m.errorHandler)
-	if m.first == nil {
-		return
-	}
+	return m.serve(pcb, func() (res *HttpFilterResult) {
+		res = newHttpFilterResult()
+		defer res.Finalize(m.ctx, m.handleError)
+		if m.first == nil {
+			return
+		}
 
-	res.Action, res.Err = fn(m.ctx, m.first)
-	return
+		res.Action, res.Err = fn(m.ctx, m.first)
+		return
+	})
 }
 
-func (m *httpFilterManager) ServeEncodeFilter(fn HttpFilterEncoderFunc) (res *HttpFilterResult) {
+func (m *httpFilterManager) ServeEncodeFilter(fn HttpFilterEncoderFunc) *HttpFilterResult {
+	var pcb api.FilterProcessCallbacks
 	if fCtx, ok := m.ctx.(*context); ok {
 		fCtx.pcb = fCtx.cb.EncoderFilterCallbacks()
+		pcb = fCtx.pcb
 	}
 
-	res = newHttpFilterResult()
-	defer res.Finalize(m.ctx, m.errorHandler)
-	if m.last == nil {
+	return m.serve(pcb, func() (res *HttpFilterResult) {
+		res = newHttpFilterResult()
+		defer res.Finalize(m.ctx, m.handleError)
+		if m.last == nil {
+			return
+		}
+
+		res.Action, res.Err = fn(m.ctx, m.last)
 		return
+	})
+}
+
+// serve executes the given phase, either synchronously or asynchronously.
+// When running asynchronously, the phase is executed within a goroutine, and a Running status is returned to Envoy.
+// Once the phase has finished, the filter chain is resumed through the process callbacks with the actual status.
+func (m *httpFilterManager) serve(pcb api.FilterProcessCallbacks, phase func() *HttpFilterResult) *HttpFilterResult {
+	if !m.async || pcb == nil {
+		return phase()
 	}
 
-	res.Action, res.Err = fn(m.ctx, m.last)
-	return
+	go func() {
+		// Last resort, in case the error handler itself panicked.
+		defer pcb.RecoverPanic()
+
+		res := phase()
+		if res.Status == api.LocalReply {
+			// The local reply has been sent, hence there is nothing left to be resumed.
+			return
+		}
+
+		pcb.Continue(res.Status)
+	}()
+
+	return &HttpFilterResult{
+		Action: ActionSkip,
+		Status: api.Running,
+	}
 }
 
 func (m *httpFilterManager) Complete() {
@@ -225,3 +439,7 @@ func (res *HttpFilterResult) Finalize(c Context, errorHandler ErrorHandler) {
 		res.Status = api.Continue
 	}
 }
+
+func isHandlerEnabled(handler HttpFilterHandler) bool {
+	return !util.IsNil(handler) && !handler.Disable()
+}
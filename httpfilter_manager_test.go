@@ -1,13 +1,17 @@
 package gonvoy
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
+	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func fakeSkipDecodePhase() HttpFilterDecoderFunc {
@@ -46,6 +50,86 @@ func fakeEncodeDataPhase() HttpFilterEncoderFunc {
 	}
 }
 
+type fakeAsyncHandler struct {
+	PassthroughHttpFilterHandler
+
+	onRequestHeader func(c Context) error
+}
+
+func (fakeAsyncHandler) Async() bool { return true }
+
+func (h fakeAsyncHandler) OnRequestHeader(c Context) error {
+	return h.onRequestHeader(c)
+}
+
+type fakeTrailerHandler struct {
+	PassthroughHttpFilterHandler
+
+	name  string
+	calls *[]string
+}
+
+func (h fakeTrailerHandler) OnRequestTrailer(c Context) error {
+	*h.calls = append(*h.calls, h.name)
+	return nil
+}
+
+func (h fakeTrailerHandler) OnResponseTrailer(c Context) error {
+	*h.calls = append(*h.calls, h.name)
+	return nil
+}
+
+type fakeChunkHandler struct {
+	PassthroughHttpFilterHandler
+
+	name  string
+	calls *[]string
+}
+
+func (h fakeChunkHandler) OnRequestChunk(c Context, chunk Body, endStream bool) error {
+	*h.calls = append(*h.calls, h.name)
+	_, err := chunk.WriteString(chunk.String() + "-" + h.name)
+	return err
+}
+
+func (h fakeChunkHandler) OnResponseChunk(c Context, chunk Body, endStream bool) error {
+	*h.calls = append(*h.calls, h.name)
+	_, err := chunk.WriteString(chunk.String() + "-" + h.name)
+	return err
+}
+
+type fakeBodyHandler struct {
+	fakeTrailerHandler
+}
+
+func (h fakeBodyHandler) OnRequestBody(c Context) error {
+	*h.calls = append(*h.calls, h.name+"-body")
+	return nil
+}
+
+func (h fakeBodyHandler) OnResponseBody(c Context) error {
+	*h.calls = append(*h.calls, h.name+"-body")
+	return nil
+}
+
+type fakeRecordHandler struct {
+	PassthroughHttpFilterHandler
+
+	name  string
+	calls *[]string
+	err   error
+}
+
+func (h fakeRecordHandler) OnRequestHeader(c Context) error {
+	*h.calls = append(*h.calls, h.name)
+	return h.err
+}
+
+func (h fakeRecordHandler) OnResponseHeader(c Context) error {
+	*h.calls = append(*h.calls, h.name)
+	return nil
+}
+
 func TestHttpFilterManager(t *testing.T) {
 
 	t.Run("set custom error handler", func(t *testing.T) {
@@ -156,6 +240,310 @@ func TestHttpFilterManager(t *testing.T) {
 		})
 	})
 
+	t.Run("execution order of trailer phases should follow the same sequences", func(t *testing.T) {
+		var calls []string
+
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().Committed().Return(false)
+		mockContext.EXPECT().StatusType().Return(api.Continue)
+
+		mgr := newHttpFilterManager(mockContext)
+		mgr.AddHandler(fakeTrailerHandler{name: "first", calls: &calls})
+		mgr.AddHandler(PassthroughHttpFilterHandler{})
+		mgr.AddHandler(fakeTrailerHandler{name: "third", calls: &calls})
+
+		res := mgr.ServeDecodeFilter(func(c Context, p HttpFilterDecodeProcessor) (HttpFilterAction, error) {
+			return ActionContinue, p.HandleOnRequestTrailer(c)
+		})
+		assert.Equal(t, api.Continue, res.Status)
+		assert.Equal(t, []string{"first", "third"}, calls)
+
+		calls = nil
+		res = mgr.ServeEncodeFilter(func(c Context, p HttpFilterEncodeProcessor) (HttpFilterAction, error) {
+			return ActionContinue, p.HandleOnResponseTrailer(c)
+		})
+		assert.Equal(t, api.Continue, res.Status)
+		assert.Equal(t, []string{"third", "first"}, calls)
+	})
+
+	t.Run("stream the body chunk by chunk", func(t *testing.T) {
+		var calls []string
+
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().Committed().Return(false)
+		mockContext.EXPECT().StatusType().Return(api.Continue)
+		mockContext.EXPECT().IsRequestBodyAccessible().Return(true)
+		mockContext.EXPECT().IsRequestBodyStreaming().Return(true)
+		mockContext.EXPECT().IsRequestBodyWritable().Return(true)
+		mockContext.EXPECT().IsResponseBodyAccessible().Return(true)
+		mockContext.EXPECT().IsResponseBodyStreaming().Return(true)
+		mockContext.EXPECT().IsResponseBodyWritable().Return(true)
+
+		mgr := newHttpFilterManager(mockContext)
+		mgr.AddHandler(fakeChunkHandler{name: "first", calls: &calls})
+		mgr.AddHandler(fakeTrailerHandler{name: "second", calls: &calls})
+		mgr.AddHandler(fakeChunkHandler{name: "third", calls: &calls})
+
+		filter := &httpFilterImpl{srv: mgr}
+
+		reqBuffer := mock_envoy.NewBufferInstance(t)
+		reqBuffer.EXPECT().Bytes().Return([]byte("chunk")).Once()
+		reqBuffer.EXPECT().Bytes().Return([]byte("chunk-first")).Once()
+		reqBuffer.EXPECT().SetString("chunk-first").Return(nil)
+		reqBuffer.EXPECT().SetString("chunk-first-third").Return(nil)
+		reqBuffer.EXPECT().Len().Return(0)
+
+		status := filter.DecodeData(reqBuffer, false)
+		assert.Equal(t, api.Continue, status)
+		assert.Equal(t, []string{"first", "third"}, calls)
+
+		calls = nil
+		respBuffer := mock_envoy.NewBufferInstance(t)
+		respBuffer.EXPECT().Bytes().Return([]byte("chunk")).Once()
+		respBuffer.EXPECT().Bytes().Return([]byte("chunk-third")).Once()
+		respBuffer.EXPECT().SetString("chunk-third").Return(nil)
+		respBuffer.EXPECT().SetString("chunk-third-first").Return(nil)
+		respBuffer.EXPECT().Len().Return(0)
+
+		status = filter.EncodeData(respBuffer, true)
+		assert.Equal(t, api.Continue, status)
+		assert.Equal(t, []string{"third", "first"}, calls)
+	})
+
+	t.Run("flush the buffered body before the trailers", func(t *testing.T) {
+		var calls []string
+
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().Committed().Return(false)
+		mockContext.EXPECT().StatusType().Return(api.Continue)
+		mockContext.EXPECT().IsRequestBodyAccessible().Return(true)
+		mockContext.EXPECT().IsRequestBodyStreaming().Return(false)
+		mockContext.EXPECT().IsResponseBodyAccessible().Return(true)
+		mockContext.EXPECT().IsResponseBodyStreaming().Return(false)
+
+		mgr := newHttpFilterManager(mockContext)
+		mgr.AddHandler(fakeBodyHandler{fakeTrailerHandler{name: "first", calls: &calls}})
+
+		filter := &httpFilterImpl{srv: mgr}
+
+		reqBuffer := mock_envoy.NewBufferInstance(t)
+		reqBuffer.EXPECT().String().Return("").Maybe()
+		mockContext.EXPECT().LoadRequestBody(reqBuffer, false).Once()
+		mockContext.EXPECT().LoadRequestBody(detachedBuffer{}, true).Once()
+		mockContext.EXPECT().LoadRequestTrailers(mock.Anything).Once()
+
+		assert.Equal(t, api.StopNoBuffer, filter.DecodeData(reqBuffer, false))
+		assert.Equal(t, api.Continue, filter.DecodeTrailers(mock_envoy.NewRequestHeaderMap(t)))
+		assert.Equal(t, []string{"first-body", "first"}, calls)
+
+		calls = nil
+		respBuffer := mock_envoy.NewBufferInstance(t)
+		respBuffer.EXPECT().String().Return("").Maybe()
+		mockContext.EXPECT().LoadResponseBody(respBuffer, false).Once()
+		mockContext.EXPECT().LoadResponseBody(detachedBuffer{}, true).Once()
+		mockContext.EXPECT().LoadResponseTrailers(mock.Anything).Once()
+
+		assert.Equal(t, api.StopNoBuffer, filter.EncodeData(respBuffer, false))
+		assert.Equal(t, api.Continue, filter.EncodeTrailers(mock_envoy.NewResponseHeaderMap(t)))
+		assert.Equal(t, []string{"first-body", "first"}, calls)
+
+		t.Run("the body is flushed only once", func(t *testing.T) {
+			calls = nil
+			mockContext.EXPECT().LoadRequestTrailers(mock.Anything).Once()
+
+			assert.Equal(t, api.Continue, filter.DecodeTrailers(mock_envoy.NewRequestHeaderMap(t)))
+			assert.Equal(t, []string{"first"}, calls)
+		})
+	})
+
+	t.Run("route the handlers by the request method and path", func(t *testing.T) {
+		var calls []string
+
+		newRoutedManager := func(t *testing.T) *httpFilterManager {
+			fcb := mock_envoy.NewFilterCallbackHandler(t)
+			fcb.EXPECT().DecoderFilterCallbacks().Return(mock_envoy.NewDecoderFilterCallbacks(t))
+			fcb.EXPECT().EncoderFilterCallbacks().Return(mock_envoy.NewEncoderFilterCallbacks(t))
+
+			ctx := fakeDummyContext(t, &internalConfig{})
+			ctx.(*context).cb = fcb
+
+			mgr := newHttpFilterManager(ctx)
+			mgr.AddHandler(fakeRecordHandler{name: "common", calls: &calls})
+			mgr.Route("POST /v1/orders/{id}", fakeRecordHandler{name: "create", calls: &calls}, fakeRecordHandler{name: "audit", calls: &calls})
+			mgr.Route("/v1/orders/{id}", fakeRecordHandler{name: "any", calls: &calls})
+			mgr.AddHandler(fakeRecordHandler{name: "last", calls: &calls})
+			require.NoError(t, mgr.err)
+			return mgr
+		}
+
+		testcases := []struct {
+			name             string
+			method           string
+			path             string
+			expectedRequest  []string
+			expectedResponse []string
+			expectedParam    string
+		}{
+			{
+				name:             "only the first matching route joins the chain",
+				method:           http.MethodPost,
+				path:             "/v1/orders/123?dry-run=true",
+				expectedRequest:  []string{"common", "create", "audit", "last"},
+				expectedResponse: []string{"last", "audit", "create", "common"},
+				expectedParam:    "123",
+			},
+			{
+				name:             "route without method",
+				method:           http.MethodGet,
+				path:             "/v1/orders/456",
+				expectedRequest:  []string{"common", "any", "last"},
+				expectedResponse: []string{"last", "any", "common"},
+				expectedParam:    "456",
+			},
+			{
+				name:             "no matching route",
+				method:           http.MethodGet,
+				path:             "/v1/users/1",
+				expectedRequest:  []string{"common", "last"},
+				expectedResponse: []string{"last", "common"},
+			},
+		}
+
+		for _, tc := range testcases {
+			t.Run(tc.name, func(t *testing.T) {
+				calls = nil
+				mgr := newRoutedManager(t)
+				mgr.route(tc.method, tc.path)
+
+				res := mgr.ServeDecodeFilter(fakeDecodeHeadersPhase())
+				assert.Equal(t, api.Continue, res.Status)
+				assert.Equal(t, tc.expectedRequest, calls)
+				assert.Equal(t, tc.expectedParam, mgr.ctx.PathParam("id"))
+
+				calls = nil
+				res = mgr.ServeEncodeFilter(fakeEncodeHeadersPhase())
+				assert.Equal(t, api.Continue, res.Status)
+				assert.Equal(t, tc.expectedResponse, calls)
+			})
+		}
+	})
+
+	t.Run("an invalid route pattern fails the filter startup", func(t *testing.T) {
+		mgr := newHttpFilterManager(fakeDummyContext(t, &internalConfig{}))
+		mgr.Route("/v1/orders/{id", PassthroughHttpFilterHandler{})
+
+		assert.ErrorContains(t, mgr.err, "invalid route pattern '/v1/orders/{id'")
+		assert.Empty(t, mgr.entries)
+	})
+
+	t.Run("middlewares wrap every phase call of the handlers", func(t *testing.T) {
+		var calls []string
+
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().Committed().Return(false)
+		mockContext.EXPECT().StatusType().Return(api.Continue)
+
+		record := func(name string) HttpFilterMiddleware {
+			return func(next HttpFilterPhaseFunc) HttpFilterPhaseFunc {
+				return func(c Context, call HttpFilterPhaseCall) error {
+					handler := call.Handler.(fakeRecordHandler)
+					calls = append(calls, name+":"+handler.name+":"+call.Phase.String())
+					return next(c, call)
+				}
+			}
+		}
+
+		mgr := newHttpFilterManager(mockContext)
+		mgr.Use(record("outer"), nil)
+		mgr.AddHandler(fakeRecordHandler{name: "first", calls: &calls})
+		mgr.AddHandler(fakeRecordHandler{name: "second", calls: &calls})
+		mgr.Use(record("inner"))
+		assert.Len(t, mgr.middlewares, 2)
+
+		res := mgr.ServeDecodeFilter(fakeDecodeHeadersPhase())
+		assert.Equal(t, api.Continue, res.Status)
+		assert.Equal(t, []string{
+			"outer:first:OnRequestHeader", "inner:first:OnRequestHeader", "first",
+			"outer:second:OnRequestHeader", "inner:second:OnRequestHeader", "second",
+		}, calls)
+
+		calls = nil
+		res = mgr.ServeEncodeFilter(fakeEncodeHeadersPhase())
+		assert.Equal(t, api.Continue, res.Status)
+		assert.Equal(t, []string{
+			"outer:second:OnResponseHeader", "inner:second:OnResponseHeader", "second",
+			"outer:first:OnResponseHeader", "inner:first:OnResponseHeader", "first",
+		}, calls)
+	})
+
+	t.Run("a middleware can tag the error or skip the phase call", func(t *testing.T) {
+		var calls []string
+		errTagged := errors.New("tagged")
+
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().Committed().Return(false).Maybe()
+		mockContext.EXPECT().StatusType().Return(api.Continue)
+
+		mgr := newHttpFilterManager(mockContext)
+		mgr.Use(func(next HttpFilterPhaseFunc) HttpFilterPhaseFunc {
+			return func(c Context, call HttpFilterPhaseCall) error {
+				if call.Handler.(fakeRecordHandler).name == "skipped" {
+					return nil
+				}
+
+				if err := next(c, call); err != nil {
+					return fmt.Errorf("%s: %w, %w", call.Phase, errTagged, err)
+				}
+
+				return nil
+			}
+		})
+		mgr.AddHandler(fakeRecordHandler{name: "skipped", calls: &calls})
+		mgr.AddHandler(fakeRecordHandler{name: "failed", calls: &calls, err: ErrBadRequest})
+
+		res := mgr.ServeDecodeFilter(func(c Context, p HttpFilterDecodeProcessor) (HttpFilterAction, error) {
+			err := p.HandleOnRequestHeader(c)
+			assert.ErrorIs(t, err, errTagged)
+			assert.ErrorIs(t, err, ErrBadRequest)
+			assert.ErrorContains(t, err, "OnRequestHeader: tagged")
+			return ActionContinue, nil
+		})
+		assert.Equal(t, api.Continue, res.Status)
+		assert.Equal(t, []string{"failed"}, calls)
+	})
+
+	t.Run("conditional handlers are skipped on every phase once the predicate doesn't match", func(t *testing.T) {
+		var calls []string
+		var evaluated int
+
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().Committed().Return(false)
+		mockContext.EXPECT().StatusType().Return(api.Continue)
+
+		matches := func(ok bool) HttpFilterPredicate {
+			return func(c Context) bool {
+				evaluated++
+				return ok
+			}
+		}
+
+		mgr := newHttpFilterManager(mockContext)
+		mgr.AddHandler(fakeRecordHandler{name: "first", calls: &calls})
+		mgr.AddHandlerWhen(matches(false), fakeRecordHandler{name: "skipped", calls: &calls})
+		mgr.AddHandlerWhen(matches(true), fakeRecordHandler{name: "matched", calls: &calls})
+		mgr.AddHandlerWhen(nil, fakeRecordHandler{name: "last", calls: &calls})
+
+		res := mgr.ServeDecodeFilter(fakeDecodeHeadersPhase())
+		assert.Equal(t, api.Continue, res.Status)
+		assert.Equal(t, []string{"first", "matched", "last"}, calls)
+
+		calls = nil
+		res = mgr.ServeEncodeFilter(fakeEncodeHeadersPhase())
+		assert.Equal(t, api.Continue, res.Status)
+		assert.Equal(t, []string{"last", "matched", "first"}, calls)
+		assert.Equal(t, 2, evaluated, "predicates should be evaluated once")
+	})
+
 	t.Run("a nil handler won't be registered", func(t *testing.T) {
 		createBadHandlerFn := func() *PassthroughHttpFilterHandler {
 			return nil
@@ -183,6 +571,7 @@ func TestHttpFilterManager(t *testing.T) {
 		mockContext := NewMockContext(t)
 		mockContext.EXPECT().Log().Return(logr.Logger{})
 		mockContext.EXPECT().GetProperty(mock.Anything, mock.Anything).Return("", nil)
+		mockContext.EXPECT().ErrorRenderer().Return(DefaultErrorRenderer)
 		mockContext.EXPECT().JSON(
 			mock.MatchedBy(func(code int) bool {
 				return assert.Equal(t, http.StatusInternalServerError, code)
@@ -237,4 +626,58 @@ func TestHttpFilterManager(t *testing.T) {
 
 		mgr.Complete()
 	})
+
+	t.Run("Serve asynchronously", func(t *testing.T) {
+		t.Run("resume the filter chain once the phase has finished", func(t *testing.T) {
+			done := make(chan api.StatusType, 1)
+			finished := make(chan struct{})
+
+			dcb := mock_envoy.NewDecoderFilterCallbacks(t)
+			dcb.EXPECT().RecoverPanic().Run(func() { close(finished) })
+			dcb.EXPECT().Continue(mock.Anything).Run(func(status api.StatusType) {
+				done <- status
+			})
+
+			fcb := mock_envoy.NewFilterCallbackHandler(t)
+			fcb.EXPECT().DecoderFilterCallbacks().Return(dcb)
+
+			ctx := fakeDummyContext(t, &internalConfig{})
+			ctx.(*context).cb = fcb
+
+			mgr := newHttpFilterManager(ctx)
+			mgr.AddHandler(fakeAsyncHandler{onRequestHeader: func(c Context) error { return nil }})
+			assert.True(t, mgr.async)
+
+			res := mgr.ServeDecodeFilter(fakeDecodeHeadersPhase())
+			assert.Equal(t, api.Running, res.Status)
+			assert.Equal(t, api.Continue, <-done)
+			<-finished
+		})
+
+		t.Run("a panic is recovered and handled by the error handler", func(t *testing.T) {
+			done := make(chan error, 1)
+			finished := make(chan struct{})
+
+			dcb := mock_envoy.NewDecoderFilterCallbacks(t)
+			dcb.EXPECT().RecoverPanic().Run(func() { close(finished) })
+
+			fcb := mock_envoy.NewFilterCallbackHandler(t)
+			fcb.EXPECT().DecoderFilterCallbacks().Return(dcb)
+
+			ctx := fakeDummyContext(t, &internalConfig{})
+			ctx.(*context).cb = fcb
+
+			mgr := newHttpFilterManager(ctx)
+			mgr.AddHandler(fakeAsyncHandler{onRequestHeader: func(c Context) error { panic("async on panic") }})
+			mgr.SetErrorHandler(func(c Context, err error) api.StatusType {
+				done <- err
+				return api.LocalReply
+			})
+
+			res := mgr.ServeDecodeFilter(fakeDecodeHeadersPhase())
+			assert.Equal(t, api.Running, res.Status)
+			assert.ErrorIs(t, <-done, ErrRuntime)
+			<-finished
+		})
+	})
 }
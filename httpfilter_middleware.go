@@ -0,0 +1,79 @@
+package gonvoy
+
+// HttpFilterPhase represents a phase of an HttpFilterHandler, e.g., OnRequestHeader.
+type HttpFilterPhase uint
+
+const (
+	PhaseOnRequestHeader HttpFilterPhase = iota
+	PhaseOnRequestBody
+	PhaseOnRequestTrailer
+	PhaseOnRequestChunk
+	PhaseOnResponseHeader
+	PhaseOnResponseBody
+	PhaseOnResponseTrailer
+	PhaseOnResponseChunk
+)
+
+func (p HttpFilterPhase) String() string {
+	switch p {
+	case PhaseOnRequestHeader:
+		return "OnRequestHeader"
+	case PhaseOnRequestBody:
+		return "OnRequestBody"
+	case PhaseOnRequestTrailer:
+		return "OnRequestTrailer"
+	case PhaseOnRequestChunk:
+		return "OnRequestChunk"
+	case PhaseOnResponseHeader:
+		return "OnResponseHeader"
+	case PhaseOnResponseBody:
+		return "OnResponseBody"
+	case PhaseOnResponseTrailer:
+		return "OnResponseTrailer"
+	case PhaseOnResponseChunk:
+		return "OnResponseChunk"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsRequest reports whether the phase belongs to the HTTP request flow.
+func (p HttpFilterPhase) IsRequest() bool {
+	return p <= PhaseOnRequestChunk
+}
+
+// HttpFilterPhaseCall describes a phase call being wrapped by an HttpFilterMiddleware.
+type HttpFilterPhaseCall struct {
+	// Handler is the HTTP filter handler whose phase is being called.
+	Handler HttpFilterHandler
+
+	// Phase is the phase being called.
+	Phase HttpFilterPhase
+}
+
+// HttpFilterPhaseFunc represents a phase call of an HttpFilterHandler, e.g., OnRequestHeader.
+type HttpFilterPhaseFunc func(c Context, call HttpFilterPhaseCall) error
+
+// HttpFilterMiddleware wraps every phase call of the HTTP filter handlers, allowing cross-cutting logic such as timing, logging, or error tagging.
+// A middleware must call the next func to proceed with the phase call, or returns without calling it to skip the phase of the handler.
+//
+// Example usage:
+//
+//	ctrl.Use(func(next HttpFilterPhaseFunc) HttpFilterPhaseFunc {
+//		return func(c Context, call HttpFilterPhaseCall) error {
+//			start := time.Now()
+//			err := next(c, call)
+//			c.Log().Info("phase executed", "handler", fmt.Sprintf("%T", call.Handler), "phase", call.Phase, "duration", time.Since(start))
+//			return err
+//		}
+//	})
+type HttpFilterMiddleware func(next HttpFilterPhaseFunc) HttpFilterPhaseFunc
+
+// chainMiddlewares wraps the phase func with the middlewares, where the first middleware is the outermost.
+func chainMiddlewares(fn HttpFilterPhaseFunc, middlewares []HttpFilterMiddleware) HttpFilterPhaseFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		fn = middlewares[i](fn)
+	}
+
+	return fn
+}
@@ -0,0 +1,139 @@
+package gonvoy
+
+import (
+	"mime"
+	"net"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// HttpFilterPredicate reports whether an HTTP filter handler should run for the request, see HttpFilterController.AddHandlerWhen.
+// It is evaluated once during the OnRequestHeader phase, hence only the request headers are available.
+type HttpFilterPredicate func(c Context) bool
+
+// And returns a predicate that matches when all the given predicates match.
+func And(predicates ...HttpFilterPredicate) HttpFilterPredicate {
+	return func(c Context) bool {
+		for _, predicate := range predicates {
+			if !predicate(c) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Or returns a predicate that matches when any of the given predicates matches.
+func Or(predicates ...HttpFilterPredicate) HttpFilterPredicate {
+	return func(c Context) bool {
+		for _, predicate := range predicates {
+			if predicate(c) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not returns a predicate that matches when the given predicate doesn't match.
+func Not(predicate HttpFilterPredicate) HttpFilterPredicate {
+	return func(c Context) bool {
+		return !predicate(c)
+	}
+}
+
+// HeaderExists returns a predicate that matches when the request header is present.
+func HeaderExists(name string) HttpFilterPredicate {
+	return func(c Context) bool {
+		return len(c.Request().Header.Values(name)) > 0
+	}
+}
+
+// HeaderMatches returns a predicate that matches when any value of the request header matches the regular expression.
+func HeaderMatches(name string, re *regexp.Regexp) HttpFilterPredicate {
+	return func(c Context) bool {
+		for _, value := range c.Request().Header.Values(name) {
+			if re.MatchString(value) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// HostMatches returns a predicate that matches when the request host, excluding the port, matches any of the glob patterns, e.g., `*.example.com`.
+// See path.Match for the pattern syntax.
+func HostMatches(patterns ...string) HttpFilterPredicate {
+	return func(c Context) bool {
+		host := c.Request().Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, host); ok {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// MethodIs returns a predicate that matches when the request method is any of the given methods.
+func MethodIs(methods ...string) HttpFilterPredicate {
+	return func(c Context) bool {
+		method := c.Request().Method
+		for _, m := range methods {
+			if strings.EqualFold(m, method) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// PathPrefix returns a predicate that matches when the request path, excluding the query string, starts with the prefix.
+func PathPrefix(prefix string) HttpFilterPredicate {
+	return func(c Context) bool {
+		return strings.HasPrefix(c.Request().URL.Path, prefix)
+	}
+}
+
+// ContentTypeIs returns a predicate that matches when the media type of the request content type is any of the given media types,
+// ignoring the parameters, e.g., `application/json` matches `application/json; charset=utf-8`.
+func ContentTypeIs(mediaTypes ...string) HttpFilterPredicate {
+	return func(c Context) bool {
+		mediaType, _, err := mime.ParseMediaType(c.Request().Header.Get(HeaderContentType))
+		if err != nil {
+			return false
+		}
+
+		for _, mt := range mediaTypes {
+			if strings.EqualFold(mt, mediaType) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// RouteNameIs returns a predicate that matches when the Envoy route name is any of the given names.
+func RouteNameIs(names ...string) HttpFilterPredicate {
+	return func(c Context) bool {
+		routeName := c.Attributes().RouteName()
+		for _, name := range names {
+			if name == routeName {
+				return true
+			}
+		}
+
+		return false
+	}
+}
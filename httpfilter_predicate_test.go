@@ -0,0 +1,71 @@
+package gonvoy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpFilterPredicate(t *testing.T) {
+	newPredicateContext := func(t *testing.T, req *http.Request) Context {
+		c := NewMockContext(t)
+		c.EXPECT().Request().Return(req).Maybe()
+		return c
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://api.example.com:8080/admin/users?limit=10", nil)
+	req.Header.Set(HeaderContentType, "application/json; charset=utf-8")
+	req.Header.Add("X-Tenant", "foo")
+	req.Header.Add("X-Tenant", "team-bar")
+
+	always := func(Context) bool { return true }
+	never := func(Context) bool { return false }
+
+	testcases := []struct {
+		name      string
+		predicate HttpFilterPredicate
+		expected  bool
+	}{
+		{name: "and all match", predicate: And(always, always), expected: true},
+		{name: "and one doesn't match", predicate: And(always, never), expected: false},
+		{name: "and without predicates", predicate: And(), expected: true},
+		{name: "or one matches", predicate: Or(never, always), expected: true},
+		{name: "or none matches", predicate: Or(never, never), expected: false},
+		{name: "not", predicate: Not(never), expected: true},
+		{name: "header exists", predicate: HeaderExists("x-tenant"), expected: true},
+		{name: "header doesn't exist", predicate: HeaderExists("x-unknown"), expected: false},
+		{name: "any header value matches", predicate: HeaderMatches("x-tenant", regexp.MustCompile(`^team-`)), expected: true},
+		{name: "no header value matches", predicate: HeaderMatches("x-tenant", regexp.MustCompile(`^admin$`)), expected: false},
+		{name: "host matches, excluding the port", predicate: HostMatches("www.example.com", "*.example.com"), expected: true},
+		{name: "host doesn't match", predicate: HostMatches("*.example.org"), expected: false},
+		{name: "method is", predicate: MethodIs(http.MethodGet, http.MethodPost), expected: true},
+		{name: "method isn't", predicate: MethodIs(http.MethodGet), expected: false},
+		{name: "path prefix", predicate: PathPrefix("/admin/"), expected: true},
+		{name: "path prefix excludes the query string", predicate: PathPrefix("/admin/users?"), expected: false},
+		{name: "content type ignores the parameters", predicate: ContentTypeIs(MIMEApplicationJSON), expected: true},
+		{name: "content type isn't", predicate: ContentTypeIs("application/xml"), expected: false},
+		{name: "combinators", predicate: And(MethodIs(http.MethodPost), Not(PathPrefix("/public"))), expected: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.predicate(newPredicateContext(t, req)))
+		})
+	}
+
+	t.Run("route name is", func(t *testing.T) {
+		fc := mock_envoy.NewFilterCallbackHandler(t)
+		fc.EXPECT().GetProperty("xds.route_name").Return("orders", nil)
+
+		c, err := NewContext(fc, contextOptions{config: &internalConfig{}})
+		require.NoError(t, err)
+
+		assert.True(t, RouteNameIs("users", "orders")(c))
+		assert.False(t, RouteNameIs("users")(c))
+	})
+}
@@ -19,6 +19,12 @@ type HttpFilterDecodeProcessor interface {
 
 	// HandleOnRequestBody manages operations during the OnRequestBody phase.
 	HandleOnRequestBody(Context) error
+
+	// HandleOnRequestTrailer manages operations during the OnRequestTrailer phase.
+	HandleOnRequestTrailer(Context) error
+
+	// HandleOnRequestChunk manages operations during the OnRequestChunk phase.
+	HandleOnRequestChunk(c Context, chunk Body, endStream bool) error
 }
 
 // HttpFilterEncodeProcessor is an interface that defines the methods for processing HTTP filter encode phases.
@@ -28,28 +34,77 @@ type HttpFilterEncodeProcessor interface {
 
 	// HandleOnResponseBody manages operations during the OnResponseBody phase.
 	HandleOnResponseBody(Context) error
+
+	// HandleOnResponseTrailer manages operations during the OnResponseTrailer phase.
+	HandleOnResponseTrailer(Context) error
+
+	// HandleOnResponseChunk manages operations during the OnResponseChunk phase.
+	HandleOnResponseChunk(c Context, chunk Body, endStream bool) error
 }
 
 type httpFilterProcessor struct {
 	HttpFilterHandler
 
+	// middlewares refers to the middlewares of the manager,
+	// hence the middlewares registered after the handler still wrap its phases.
+	middlewares *[]HttpFilterMiddleware
+
+	// when is the predicate of the handler, see HttpFilterController.AddHandlerWhen.
+	// Once the predicate doesn't match, every phase of the handler is skipped.
+	when    HttpFilterPredicate
+	skipped bool
+
 	prev HttpFilterProcessor
 	next HttpFilterProcessor
 }
 
-func newHttpFilterProcessor(hf HttpFilterHandler) *httpFilterProcessor {
+func newHttpFilterProcessor(hf HttpFilterHandler, middlewares *[]HttpFilterMiddleware) *httpFilterProcessor {
 	return &httpFilterProcessor{
 		HttpFilterHandler: hf,
+		middlewares:       middlewares,
+	}
+}
+
+// call executes the phase of the handler, wrapped by the middlewares (if any).
+// When the handler implements HttpFilterErrorHandler, the returned error is tagged with the handler.
+func (p *httpFilterProcessor) call(c Context, phase HttpFilterPhase, fn func(c Context) error) error {
+	err := p.callWithMiddlewares(c, phase, fn)
+	if err == nil {
+		return nil
+	}
+
+	if eh, ok := p.HttpFilterHandler.(HttpFilterErrorHandler); ok {
+		return &handlerError{handler: eh, err: err}
+	}
+
+	return err
+}
+
+func (p *httpFilterProcessor) callWithMiddlewares(c Context, phase HttpFilterPhase, fn func(c Context) error) error {
+	if p.middlewares == nil || len(*p.middlewares) == 0 {
+		return fn(c)
 	}
+
+	call := HttpFilterPhaseCall{Handler: p.HttpFilterHandler, Phase: phase}
+	return chainMiddlewares(func(c Context, _ HttpFilterPhaseCall) error {
+		return fn(c)
+	}, *p.middlewares)(c, call)
 }
 
 func (p *httpFilterProcessor) HandleOnRequestHeader(c Context) error {
-	if err := p.OnRequestHeader(c); err != nil {
-		return err
+	if p.when != nil {
+		// The predicate is evaluated once, and the result is remembered for the subsequent phases.
+		p.skipped = !p.when(c)
 	}
 
-	if c.Committed() {
-		return nil
+	if !p.skipped {
+		if err := p.call(c, PhaseOnRequestHeader, p.OnRequestHeader); err != nil {
+			return err
+		}
+
+		if c.Committed() {
+			return nil
+		}
 	}
 
 	if p.next != nil {
@@ -60,28 +115,70 @@ func (p *httpFilterProcessor) HandleOnRequestHeader(c Context) error {
 }
 
 func (p *httpFilterProcessor) HandleOnRequestBody(c Context) error {
-	if err := p.OnRequestBody(c); err != nil {
-		return err
+	if !p.skipped {
+		if err := p.call(c, PhaseOnRequestBody, p.OnRequestBody); err != nil {
+			return err
+		}
+
+		if c.Committed() {
+			return nil
+		}
 	}
 
-	if c.Committed() {
-		return nil
+	if p.next != nil {
+		return p.next.HandleOnRequestBody(c)
+	}
+
+	return nil
+}
+
+func (p *httpFilterProcessor) HandleOnRequestTrailer(c Context) error {
+	if th, ok := p.HttpFilterHandler.(HttpFilterTrailerHandler); ok && !p.skipped {
+		if err := p.call(c, PhaseOnRequestTrailer, th.OnRequestTrailer); err != nil {
+			return err
+		}
+
+		if c.Committed() {
+			return nil
+		}
 	}
 
 	if p.next != nil {
-		return p.next.HandleOnRequestBody(c)
+		return p.next.HandleOnRequestTrailer(c)
 	}
 
 	return nil
 }
 
-func (p *httpFilterProcessor) HandleOnResponseHeader(c Context) error {
-	if err := p.OnResponseHeader(c); err != nil {
-		return err
+func (p *httpFilterProcessor) HandleOnRequestChunk(c Context, chunk Body, endStream bool) error {
+	if ch, ok := p.HttpFilterHandler.(HttpFilterChunkHandler); ok && !p.skipped {
+		if err := p.call(c, PhaseOnRequestChunk, func(c Context) error {
+			return ch.OnRequestChunk(c, chunk, endStream)
+		}); err != nil {
+			return err
+		}
+
+		if c.Committed() {
+			return nil
+		}
 	}
 
-	if c.Committed() {
-		return nil
+	if p.next != nil {
+		return p.next.HandleOnRequestChunk(c, chunk, endStream)
+	}
+
+	return nil
+}
+
+func (p *httpFilterProcessor) HandleOnResponseHeader(c Context) error {
+	if !p.skipped {
+		if err := p.call(c, PhaseOnResponseHeader, p.OnResponseHeader); err != nil {
+			return err
+		}
+
+		if c.Committed() {
+			return nil
+		}
 	}
 
 	if p.prev != nil {
@@ -92,16 +189,56 @@ func (p *httpFilterProcessor) HandleOnResponseHeader(c Context) error {
 }
 
 func (p *httpFilterProcessor) HandleOnResponseBody(c Context) error {
-	if err := p.OnResponseBody(c); err != nil {
-		return err
+	if !p.skipped {
+		if err := p.call(c, PhaseOnResponseBody, p.OnResponseBody); err != nil {
+			return err
+		}
+
+		if c.Committed() {
+			return nil
+		}
 	}
 
-	if c.Committed() {
-		return nil
+	if p.prev != nil {
+		return p.prev.HandleOnResponseBody(c)
+	}
+
+	return nil
+}
+
+func (p *httpFilterProcessor) HandleOnResponseTrailer(c Context) error {
+	if th, ok := p.HttpFilterHandler.(HttpFilterTrailerHandler); ok && !p.skipped {
+		if err := p.call(c, PhaseOnResponseTrailer, th.OnResponseTrailer); err != nil {
+			return err
+		}
+
+		if c.Committed() {
+			return nil
+		}
 	}
 
 	if p.prev != nil {
-		return p.prev.HandleOnResponseBody(c)
+		return p.prev.HandleOnResponseTrailer(c)
+	}
+
+	return nil
+}
+
+func (p *httpFilterProcessor) HandleOnResponseChunk(c Context, chunk Body, endStream bool) error {
+	if ch, ok := p.HttpFilterHandler.(HttpFilterChunkHandler); ok && !p.skipped {
+		if err := p.call(c, PhaseOnResponseChunk, func(c Context) error {
+			return ch.OnResponseChunk(c, chunk, endStream)
+		}); err != nil {
+			return err
+		}
+
+		if c.Committed() {
+			return nil
+		}
+	}
+
+	if p.prev != nil {
+		return p.prev.HandleOnResponseChunk(c, chunk, endStream)
 	}
 
 	return nil
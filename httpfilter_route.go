@@ -0,0 +1,137 @@
+package gonvoy
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// httpFilterRouter is implemented by an HttpFilterServer which selects the filter handlers per request.
+type httpFilterRouter interface {
+	// route selects the filter handlers that match the request, prior to the DecodeHeaders phase.
+	route(method, path string)
+}
+
+// httpFilterRoute represents a parsed route pattern, i.e., `[METHOD ]/path/{param}/{rest...}`.
+type httpFilterRoute struct {
+	pattern  string
+	method   string
+	segments []routeSegment
+}
+
+// routeSegment represents a path segment of the route pattern.
+// A segment is either a literal, a parameter (`{name}`), or a wildcard parameter (`{name...}`).
+type routeSegment struct {
+	literal  string
+	param    string
+	wildcard bool
+}
+
+// parseHttpFilterRoute parses the route pattern, which optionally starts with a method followed by a space, e.g., `POST /v1/orders/{id}`.
+// A parameter spans a single path segment, while a wildcard parameter, which must be the last segment, spans the remaining path.
+func parseHttpFilterRoute(pattern string) (*httpFilterRoute, error) {
+	r := &httpFilterRoute{pattern: pattern}
+
+	path := strings.TrimSpace(pattern)
+	if method, rest, found := strings.Cut(path, " "); found {
+		r.method = method
+		path = strings.TrimSpace(rest)
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return nil, errors.New("path must start with '/'")
+	}
+
+	seen := make(map[string]bool)
+	parts := strings.Split(path[1:], "/")
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "{") {
+			if strings.ContainsAny(part, "{}") {
+				return nil, fmt.Errorf("invalid segment '%s', a parameter must span the whole segment", part)
+			}
+
+			r.segments = append(r.segments, routeSegment{literal: part})
+			continue
+		}
+
+		if !strings.HasSuffix(part, "}") {
+			return nil, fmt.Errorf("invalid segment '%s', missing closing brace", part)
+		}
+
+		seg := routeSegment{param: part[1 : len(part)-1]}
+		if name, found := strings.CutSuffix(seg.param, "..."); found {
+			if i != len(parts)-1 {
+				return nil, fmt.Errorf("wildcard parameter '%s' must be the last segment", part)
+			}
+
+			seg.param = name
+			seg.wildcard = true
+		}
+
+		if seg.param == "" {
+			return nil, fmt.Errorf("invalid segment '%s', missing parameter name", part)
+		}
+
+		if seen[seg.param] {
+			return nil, fmt.Errorf("duplicate parameter '%s'", seg.param)
+		}
+
+		seen[seg.param] = true
+		r.segments = append(r.segments, seg)
+	}
+
+	return r, nil
+}
+
+// match reports whether the request method and path match the route, along with the path parameters.
+// The path may include the query string, which is ignored.
+func (r *httpFilterRoute) match(method, path string) (map[string]string, bool) {
+	if r.method != "" && r.method != method {
+		return nil, false
+	}
+
+	path, _, _ = strings.Cut(path, "?")
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	parts := strings.Split(path[1:], "/")
+	for i, seg := range r.segments {
+		if i >= len(parts) {
+			return nil, false
+		}
+
+		if seg.wildcard {
+			params[seg.param] = unescapePathParam(strings.Join(parts[i:], "/"))
+			return params, true
+		}
+
+		switch {
+		case seg.param == "":
+			if seg.literal != parts[i] {
+				return nil, false
+			}
+		case parts[i] == "":
+			// A parameter never matches an empty segment.
+			return nil, false
+		default:
+			params[seg.param] = unescapePathParam(parts[i])
+		}
+	}
+
+	if len(parts) != len(r.segments) {
+		return nil, false
+	}
+
+	return params, true
+}
+
+func unescapePathParam(s string) string {
+	if v, err := url.PathUnescape(s); err == nil {
+		return v
+	}
+
+	return s
+}
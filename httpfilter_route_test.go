@@ -0,0 +1,103 @@
+package gonvoy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpFilterRoute(t *testing.T) {
+	t.Run("match the request", func(t *testing.T) {
+		testcases := []struct {
+			name           string
+			pattern        string
+			method         string
+			path           string
+			expectedMatch  bool
+			expectedParams map[string]string
+		}{
+			{
+				name:           "literal path matches any method",
+				pattern:        "/v1/orders",
+				method:         "DELETE",
+				path:           "/v1/orders?limit=10",
+				expectedMatch:  true,
+				expectedParams: map[string]string{},
+			},
+			{
+				name:           "method and parameter",
+				pattern:        "POST /v1/orders/{id}",
+				method:         "POST",
+				path:           "/v1/orders/order%201",
+				expectedMatch:  true,
+				expectedParams: map[string]string{"id": "order 1"},
+			},
+			{
+				name:          "method mismatch",
+				pattern:       "POST /v1/orders/{id}",
+				method:        "GET",
+				path:          "/v1/orders/1",
+				expectedMatch: false,
+			},
+			{
+				name:          "parameter never matches an empty segment",
+				pattern:       "/v1/orders/{id}",
+				method:        "GET",
+				path:          "/v1/orders/",
+				expectedMatch: false,
+			},
+			{
+				name:          "extra segments",
+				pattern:       "/v1/orders/{id}",
+				method:        "GET",
+				path:          "/v1/orders/1/items",
+				expectedMatch: false,
+			},
+			{
+				name:           "wildcard matches the remaining path",
+				pattern:        "GET /static/{path...}",
+				method:         "GET",
+				path:           "/static/css/main.css",
+				expectedMatch:  true,
+				expectedParams: map[string]string{"path": "css/main.css"},
+			},
+			{
+				name:          "wildcard requires a segment",
+				pattern:       "GET /static/{path...}",
+				method:        "GET",
+				path:          "/static",
+				expectedMatch: false,
+			},
+		}
+
+		for _, tc := range testcases {
+			t.Run(tc.name, func(t *testing.T) {
+				route, err := parseHttpFilterRoute(tc.pattern)
+				require.NoError(t, err)
+
+				params, ok := route.match(tc.method, tc.path)
+				assert.Equal(t, tc.expectedMatch, ok)
+				assert.Equal(t, tc.expectedParams, params)
+			})
+		}
+	})
+
+	t.Run("invalid patterns", func(t *testing.T) {
+		patterns := []string{
+			"v1/orders",
+			"GET v1/orders",
+			"/v1/orders/{id",
+			"/v1/orders/id}",
+			"/v1/orders/{}",
+			"/v1/orders/order-{id}",
+			"/v1/{rest...}/items",
+			"/v1/{id}/items/{id}",
+		}
+
+		for _, pattern := range patterns {
+			_, err := parseHttpFilterRoute(pattern)
+			assert.Error(t, err, pattern)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package gonvoy
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedFilterConfig struct {
+	Name    string `json:"name"`
+	Version string `json:"version" default:"v1"`
+}
+
+type typedFilter struct {
+	config *typedFilterConfig
+	deps   Deps
+}
+
+func (f *typedFilter) OnBegin(c RuntimeContext, ctrl HttpFilterController) error { return nil }
+func (f *typedFilter) OnComplete(c Context) error                                { return nil }
+
+func TestTypedHttpFilterFactoryFunc(t *testing.T) {
+	t.Run("filter is created with the filter config and its dependencies", func(t *testing.T) {
+		cfg := &typedFilterConfig{Name: "foo"}
+		cache := newInternalCache(0, 0, nil)
+		logger := logr.Discard()
+
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().GetFilterConfig().Return(cfg)
+		mockContext.EXPECT().GetCache().Return(cache)
+		mockContext.EXPECT().Metrics().Return(nil)
+		mockContext.EXPECT().Log().Return(logger)
+
+		fn := TypedHttpFilterFactoryFunc[typedFilterConfig](func(cfg *typedFilterConfig, deps Deps) HttpFilter {
+			return &typedFilter{config: cfg, deps: deps}
+		})
+
+		filter, err := fn.build(mockContext)
+		require.NoError(t, err)
+
+		tf := filter.(*typedFilter)
+		assert.Same(t, cfg, tf.config)
+		assert.Same(t, cache, tf.deps.Cache)
+		assert.Equal(t, logger, tf.deps.Logger)
+	})
+
+	t.Run("filter without config is ignored", func(t *testing.T) {
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().GetFilterConfig().Return(nil)
+
+		fn := TypedHttpFilterFactoryFunc[typedFilterConfig](func(cfg *typedFilterConfig, deps Deps) HttpFilter {
+			return &typedFilter{config: cfg, deps: deps}
+		})
+
+		_, err := fn.build(mockContext)
+		assert.ErrorContains(t, err, "unexpected filter config type '<nil>', expecting '*gonvoy.typedFilterConfig'")
+	})
+
+	t.Run("unexpected filter config type", func(t *testing.T) {
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().GetFilterConfig().Return(map[string]interface{}{})
+
+		fn := TypedHttpFilterFactoryFunc[typedFilterConfig](func(cfg *typedFilterConfig, deps Deps) HttpFilter {
+			return &typedFilter{config: cfg, deps: deps}
+		})
+
+		_, err := fn.build(mockContext)
+		assert.ErrorContains(t, err, "unexpected filter config type 'map[string]interface {}', expecting '*gonvoy.typedFilterConfig'")
+	})
+
+	t.Run("factory returns nil filter", func(t *testing.T) {
+		mockContext := NewMockContext(t)
+		mockContext.EXPECT().GetFilterConfig().Return(&typedFilterConfig{})
+		mockContext.EXPECT().GetCache().Return(nil)
+		mockContext.EXPECT().Metrics().Return(nil)
+		mockContext.EXPECT().Log().Return(logr.Discard())
+
+		fn := TypedHttpFilterFactoryFunc[typedFilterConfig](func(cfg *typedFilterConfig, deps Deps) HttpFilter {
+			return nil
+		})
+
+		_, err := fn.build(mockContext)
+		assert.ErrorContains(t, err, "filterFactoryFunc shouldn't return nil")
+	})
+}
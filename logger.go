@@ -15,6 +15,35 @@ type envoyLogger interface {
 	Log(level api.LogType, msg string)
 }
 
+// envoyGlobalLogger sends log messages to the Envoy Log without a filter callback, e.g., during the filter configuration lifecycle.
+type envoyGlobalLogger struct{}
+
+func (envoyGlobalLogger) Log(level api.LogType, msg string) {
+	switch level {
+	case api.Trace:
+		api.LogTrace(msg)
+	case api.Debug:
+		api.LogDebug(msg)
+	case api.Warn:
+		api.LogWarn(msg)
+	case api.Error:
+		api.LogError(msg)
+	case api.Critical:
+		api.LogCritical(msg)
+	default:
+		api.LogInfo(msg)
+	}
+}
+
+// newConfigLogger returns the given logger, or the Envoy global logger when the given logger is unset.
+func newConfigLogger(logger logr.Logger) logr.Logger {
+	if logger.GetSink() == nil {
+		return newLogger(envoyGlobalLogger{})
+	}
+
+	return logger
+}
+
 // logWriter is a custom implementation of io.Writer that writes log messages to a buffer.
 type logWriter struct {
 	mu  sync.Mutex
@@ -2,13 +2,18 @@ package gonvoy
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
 )
 
+// DefaultHistogramBuckets are the default upper bounds of the histogram buckets,
+// which are tailored for request latencies measured in milliseconds.
+var DefaultHistogramBuckets = []uint64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
 // Metrics is an interface for defining various types of metrics.
-// At presents, it only supports Gauge and Counter.
 type Metrics interface {
 	// Gauge sets gauge statistics that can record both increasing and decreasing metrics. E.g., current active requests.
 	Gauge(name string, labelKeyValues ...string) api.GaugeMetric
@@ -16,18 +21,34 @@ type Metrics interface {
 	// Counter sets counter statistics that only record for increase, but never decrease metrics. E.g., total requests.
 	Counter(name string, labelKeyValues ...string) api.CounterMetric
 
-	// Histogram is still a WIP
-	Histogram(name string, labelKeyValues ...string) api.HistogramMetric
+	// Histogram sets histogram statistics that record the distribution of values. E.g., request latencies.
+	Histogram(name string, labelKeyValues ...string) HistogramMetric
+}
+
+// HistogramMetric is an interface for recording values into a histogram.
+//
+// Since Envoy doesn't expose histogram to the Go plugin, a histogram is built from a set of counters,
+// following the Prometheus convention, given a histogram named `latency` with the `host=example.com` label:
+//   - `latency_bucket_host=example.com_le=<upper-bound>` for every bucket, including `le=+inf`, counting the values less than or equal to the upper bound.
+//   - `latency_sum_host=example.com` for the sum of all recorded values.
+//   - `latency_count_host=example.com` for the number of recorded values.
+type HistogramMetric interface {
+	// Record records a value into the histogram.
+	Record(value uint64)
 }
 
+// newMetrics creates a metric registry, which holds the defined metric handles,
+// so that a metric is only defined once to Envoy regardless of how many requests are using it.
+// The registry is safe for concurrent use, as it is shared across requests and Envoy worker threads.
 func newMetrics(counterFunc counterFunc, gaugeFunc gaugeFunc, histogramFunc histogramFunc) *metrics {
 	return &metrics{
 		counterFunc:   counterFunc,
 		gaugeFunc:     gaugeFunc,
 		histogramFunc: histogramFunc,
 
-		counterMap: make(map[string]api.CounterMetric),
-		gaugeMap:   make(map[string]api.GaugeMetric),
+		counterMap:   make(map[string]api.CounterMetric),
+		gaugeMap:     make(map[string]api.GaugeMetric),
+		histogramMap: make(map[string]HistogramMetric),
 	}
 }
 
@@ -36,15 +57,17 @@ var _ Metrics = &metrics{}
 type (
 	counterFunc   func(name string) api.CounterMetric
 	gaugeFunc     func(name string) api.GaugeMetric
-	histogramFunc func(name string) api.HistogramMetric
+	histogramFunc func(name string, labelKeyValues ...string) HistogramMetric
 
 	metrics struct {
 		counterFunc   counterFunc
 		gaugeFunc     gaugeFunc
 		histogramFunc histogramFunc
 
-		counterMap map[string]api.CounterMetric
-		gaugeMap   map[string]api.GaugeMetric
+		mu           sync.RWMutex
+		counterMap   map[string]api.CounterMetric
+		gaugeMap     map[string]api.GaugeMetric
+		histogramMap map[string]HistogramMetric
 	}
 )
 
@@ -54,13 +77,9 @@ func (m *metrics) Gauge(name string, labelKeyValues ...string) api.GaugeMetric {
 	}
 
 	stats := createStatsName(name, labelKeyValues...)
-	gauge, ok := m.gaugeMap[stats]
-	if !ok {
-		gauge = m.gaugeFunc(stats)
-		m.gaugeMap[stats] = gauge
-	}
-
-	return gauge
+	return loadOrDefineMetric(&m.mu, m.gaugeMap, stats, func() api.GaugeMetric {
+		return m.gaugeFunc(stats)
+	})
 }
 
 func (m *metrics) Counter(name string, labelKeyValues ...string) api.CounterMetric {
@@ -69,17 +88,127 @@ func (m *metrics) Counter(name string, labelKeyValues ...string) api.CounterMetr
 	}
 
 	stats := createStatsName(name, labelKeyValues...)
-	counter, ok := m.counterMap[stats]
-	if !ok {
-		counter = m.counterFunc(stats)
-		m.counterMap[stats] = counter
+	return loadOrDefineMetric(&m.mu, m.counterMap, stats, func() api.CounterMetric {
+		return m.counterFunc(stats)
+	})
+}
+
+func (m *metrics) Histogram(name string, labelKeyValues ...string) HistogramMetric {
+	if m.histogramFunc == nil {
+		panic("metric histogram handler is not set")
 	}
 
-	return counter
+	stats := createStatsName(name, labelKeyValues...)
+	return loadOrDefineMetric(&m.mu, m.histogramMap, stats, func() HistogramMetric {
+		return m.histogramFunc(name, labelKeyValues...)
+	})
+}
+
+// reset drops all the defined metric handles, hence the next lookup defines the metric again.
+func (m *metrics) reset() {
+	m.renew(func() {})
 }
 
-func (m *metrics) Histogram(name string, labelKeyValues ...string) api.HistogramMetric {
-	panic("NOT IMPLEMENTED")
+// renew drops all the defined metric handles, and calls the renew function while holding the registry lock.
+// Since the metrics are only defined while holding the lock, the renew function may replace what the metrics are defined against,
+// e.g., the Envoy config callbacks once the root filter configuration is reloaded, as the metric handles are bound to the previous ones.
+func (m *metrics) renew(fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fn()
+	clear(m.counterMap)
+	clear(m.gaugeMap)
+	clear(m.histogramMap)
+}
+
+// noopMetrics is the metric registry of the configs without the Envoy config callbacks, e.g., in tests,
+// where every metric is discarded.
+type noopMetrics struct{}
+
+var _ Metrics = noopMetrics{}
+
+func (noopMetrics) Gauge(string, ...string) api.GaugeMetric     { return noopMetric{} }
+func (noopMetrics) Counter(string, ...string) api.CounterMetric { return noopMetric{} }
+func (noopMetrics) Histogram(string, ...string) HistogramMetric { return noopMetric{} }
+
+// noopMetric discards every recorded value, it is used as a counter, gauge, and histogram alike.
+type noopMetric struct{}
+
+func (noopMetric) Increment(int64) {}
+func (noopMetric) Get() uint64     { return 0 }
+func (noopMetric) Record(uint64)   {}
+
+// loadOrDefineMetric returns the metric handle from the registry, otherwise it defines the metric and stores it.
+// The read lock is used on the hot path, while the write lock is only held on the first definition.
+func loadOrDefineMetric[T any](mu *sync.RWMutex, registry map[string]T, stats string, define func() T) T {
+	mu.RLock()
+	metric, ok := registry[stats]
+	mu.RUnlock()
+	if ok {
+		return metric
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Re-check, the metric might have been defined by another request in the meantime.
+	if metric, ok := registry[stats]; ok {
+		return metric
+	}
+
+	metric = define()
+	registry[stats] = metric
+	return metric
+}
+
+// newHistogram creates a histogram from a set of counters defined through the counterFunc, see HistogramMetric.
+// The buckets are expected to be sorted in ascending order.
+func newHistogram(counterFunc counterFunc, buckets []uint64, name string, labelKeyValues ...string) *histogram {
+	h := &histogram{
+		buckets:  buckets,
+		counters: make([]api.CounterMetric, len(buckets)),
+		sum:      counterFunc(createStatsName(name+"_sum", labelKeyValues...)),
+		count:    counterFunc(createStatsName(name+"_count", labelKeyValues...)),
+	}
+
+	bucketLabels := func(le string) []string {
+		labels := make([]string, 0, len(labelKeyValues)+2)
+		labels = append(labels, labelKeyValues...)
+		return append(labels, "le", le)
+	}
+
+	for i, bucket := range buckets {
+		h.counters[i] = counterFunc(createStatsName(name+"_bucket", bucketLabels(strconv.FormatUint(bucket, 10))...))
+	}
+
+	h.inf = counterFunc(createStatsName(name+"_bucket", bucketLabels("+inf")...))
+	return h
+}
+
+var _ HistogramMetric = &histogram{}
+
+// histogram holds no mutable state on the Go side, all the state lives in Envoy counters,
+// hence it is safe to record from any phase, including OnComplete.
+type histogram struct {
+	buckets  []uint64
+	counters []api.CounterMetric
+	inf      api.CounterMetric
+	sum      api.CounterMetric
+	count    api.CounterMetric
+}
+
+func (h *histogram) Record(value uint64) {
+	// Buckets are cumulative, a value is counted on every bucket whose upper bound is greater than or equal to the value.
+	for i, bucket := range h.buckets {
+		if value <= bucket {
+			h.counters[i].Increment(1)
+		}
+	}
+
+	h.inf.Increment(1)
+	h.sum.Increment(int64(value))
+	h.count.Increment(1)
 }
 
 // Create an Envoy stats name with the given name and labels.
@@ -1,9 +1,12 @@
 package gonvoy
 
 import (
+	"slices"
+	"sync"
 	"testing"
 
 	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -35,3 +38,134 @@ func TestMetrics(t *testing.T) {
 	m.Counter("foo", "key", "value", "key1", "value2")
 	m.Gauge("foo", "key", "value", "key1", "value2")
 }
+
+func TestMetrics_Histogram(t *testing.T) {
+	t.Run("histogram handler is not set", func(t *testing.T) {
+		m := newMetrics(nil, nil, nil)
+		assert.Panics(t, func() { m.Histogram("foo") })
+	})
+
+	t.Run("record into cumulative buckets", func(t *testing.T) {
+		counters := make(map[string]*mock_envoy.CounterMetric)
+		cc := mock_envoy.NewConfigCallbackHandler(t)
+		cc.EXPECT().DefineCounterMetric(mock.Anything).RunAndReturn(func(name string) api.CounterMetric {
+			counters[name] = mock_envoy.NewCounterMetric(t)
+			return counters[name]
+		})
+
+		cfg := newInternalConfig(ConfigOptions{
+			MetricsPrefix:    "prefix_",
+			HistogramBuckets: []uint64{100, 10, 10},
+		})
+		cfg.callbacks = cc
+
+		m := newMetrics(cfg.defineCounterMetric, cfg.defineGaugeMetric, cfg.defineHistogramMetric)
+		h := m.Histogram("latency", "host", "example.com")
+		assert.Same(t, h, m.Histogram("latency", "host", "example.com"))
+		assert.Len(t, counters, 5)
+
+		counters["prefix_latency_bucket_host=example.com_le=100"].EXPECT().Increment(int64(1)).Once()
+		counters["prefix_latency_bucket_host=example.com_le=+inf"].EXPECT().Increment(int64(1)).Once()
+		counters["prefix_latency_sum_host=example.com"].EXPECT().Increment(int64(50)).Once()
+		counters["prefix_latency_count_host=example.com"].EXPECT().Increment(int64(1)).Once()
+
+		h.Record(50)
+	})
+}
+
+func TestNewHistogramBuckets(t *testing.T) {
+	assert.Equal(t, DefaultHistogramBuckets, newHistogramBuckets(nil))
+	assert.Equal(t, []uint64{1, 5, 10}, newHistogramBuckets([]uint64{10, 1, 5, 5}))
+
+	t.Run("default buckets are not shared", func(t *testing.T) {
+		expected := slices.Clone(DefaultHistogramBuckets)
+
+		buckets := newHistogramBuckets(nil)
+		buckets[0] = 0
+		_ = append(buckets[:1], 1)
+		assert.Equal(t, expected, DefaultHistogramBuckets)
+	})
+}
+
+func TestMetrics_Registry(t *testing.T) {
+	cc := mock_envoy.NewConfigCallbackHandler(t)
+	cc.EXPECT().DefineCounterMetric("foo_key=value").Return(mock_envoy.NewCounterMetric(t)).Once()
+
+	cfg := newInternalConfig(ConfigOptions{})
+	cfg.callbacks = cc
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			c, err := NewContext(mock_envoy.NewFilterCallbackHandler(t), contextOptions{config: cfg})
+			assert.NoError(t, err)
+			assert.Same(t, cfg.metrics, c.Metrics())
+
+			c.Metrics().Counter("foo", "key", "value")
+		}()
+	}
+	wg.Wait()
+
+	cfg.metrics.reset()
+	assert.Empty(t, cfg.metrics.counterMap)
+}
+
+type fakeConfigCallbacks struct {
+	defined int
+}
+
+func (f *fakeConfigCallbacks) DefineCounterMetric(name string) api.CounterMetric {
+	f.defined++
+	return fakeCounterMetric{}
+}
+
+func (f *fakeConfigCallbacks) DefineGaugeMetric(name string) api.GaugeMetric {
+	panic("unimplemented")
+}
+
+type fakeCounterMetric struct{}
+
+func (fakeCounterMetric) Increment(offset int64) {}
+func (fakeCounterMetric) Get() uint64            { return 0 }
+func (fakeCounterMetric) Record(value uint64)    {}
+
+// BenchmarkMetrics_Counter compares the shared metric registry against defining the metrics on every request.
+func BenchmarkMetrics_Counter(b *testing.B) {
+	labels := []string{"host", "example.com", "method", "GET", "response_code", "200"}
+
+	b.Run("per-request registry", func(b *testing.B) {
+		cfg := newInternalConfig(ConfigOptions{MetricsPrefix: "bench_"})
+		cfg.callbacks = &fakeConfigCallbacks{}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m := newMetrics(cfg.defineCounterMetric, cfg.defineGaugeMetric, cfg.defineHistogramMetric)
+			m.Counter("requests_total", labels...).Increment(1)
+		}
+	})
+
+	b.Run("shared registry", func(b *testing.B) {
+		cfg := newInternalConfig(ConfigOptions{MetricsPrefix: "bench_"})
+		cfg.callbacks = &fakeConfigCallbacks{}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cfg.metrics.Counter("requests_total", labels...).Increment(1)
+		}
+	})
+
+	b.Run("shared registry in parallel", func(b *testing.B) {
+		cfg := newInternalConfig(ConfigOptions{MetricsPrefix: "bench_"})
+		cfg.callbacks = &fakeConfigCallbacks{}
+
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				cfg.metrics.Counter("requests_total", labels...).Increment(1)
+			}
+		})
+	})
+}
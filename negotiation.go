@@ -0,0 +1,267 @@
+package gonvoy
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Renderer renders a value into a response body of a media type, see ConfigOptions.Renderers.
+type Renderer interface {
+	// Render renders the value into the response body.
+	//
+	Render(v interface{}) ([]byte, error)
+}
+
+// RendererFunc is an adapter to allow the use of an ordinary function as a Renderer.
+type RendererFunc func(v interface{}) ([]byte, error)
+
+func (fn RendererFunc) Render(v interface{}) ([]byte, error) {
+	return fn(v)
+}
+
+var (
+	// JSONRenderer renders the value with json.Marshal, except a []byte which is considered as an encoded JSON.
+	JSONRenderer = RendererFunc(func(v interface{}) ([]byte, error) {
+		if b, ok := v.([]byte); ok {
+			return b, nil
+		}
+
+		return json.Marshal(v)
+	})
+
+	// XMLRenderer renders the value with xml.Marshal, except a []byte which is considered as an encoded XML.
+	XMLRenderer = RendererFunc(func(v interface{}) ([]byte, error) {
+		if b, ok := v.([]byte); ok {
+			return b, nil
+		}
+
+		return xml.Marshal(v)
+	})
+
+	// TextRenderer renders the value as a plain text, e.g., through its String or Error method.
+	TextRenderer = RendererFunc(func(v interface{}) ([]byte, error) {
+		return []byte(toText(v)), nil
+	})
+
+	// HTMLRenderer renders the value as a plain text within a minimal HTML page, where the text is escaped.
+	HTMLRenderer = RendererFunc(func(v interface{}) ([]byte, error) {
+		text := html.EscapeString(toText(v))
+		return []byte(fmt.Sprintf("<!DOCTYPE html><html><head><title>%s</title></head><body><h1>%s</h1></body></html>", text, text)), nil
+	})
+)
+
+// DefaultRenderers returns the renderers used when ConfigOptions.Renderers is unset, i.e., JSON, XML, HTML, and plain text.
+func DefaultRenderers() map[string]Renderer {
+	return map[string]Renderer{
+		MIMEApplicationJSON: JSONRenderer,
+		MIMEApplicationXML:  XMLRenderer,
+		MIMETextHTML:        HTMLRenderer,
+		MIMETextPlain:       TextRenderer,
+	}
+}
+
+func toText(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case fmt.Stringer:
+		return v.String()
+	case error:
+		return v.Error()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// contentNegotiation selects the renderer of a response based on the request Accept header.
+type contentNegotiation struct {
+	renderers map[string]Renderer
+
+	// offers are the media types of the renderers in order of preference, where the fallback media type comes first.
+	offers   []string
+	fallback string
+}
+
+func newContentNegotiation(renderers map[string]Renderer, fallback string) *contentNegotiation {
+	if len(renderers) == 0 {
+		renderers = DefaultRenderers()
+	}
+
+	fallback = strings.ToLower(fallback)
+	if fallback == "" {
+		fallback = MIMEApplicationJSON
+	}
+
+	renderers = cloneRenderers(renderers)
+	if _, ok := renderers[fallback]; !ok {
+		// The fallback media type must always be rendered.
+		renderers[fallback] = JSONRenderer
+	}
+
+	n := &contentNegotiation{
+		renderers: renderers,
+		fallback:  fallback,
+		offers:    []string{fallback},
+	}
+
+	others := make([]string, 0, len(renderers))
+	for mediaType := range renderers {
+		if mediaType != fallback {
+			others = append(others, mediaType)
+		}
+	}
+
+	sort.Strings(others)
+	n.offers = append(n.offers, others...)
+	return n
+}
+
+// cloneRenderers clones the renderers, where the media types are lowercased and the nil renderers are dropped.
+func cloneRenderers(renderers map[string]Renderer) map[string]Renderer {
+	clone := make(map[string]Renderer, len(renderers)+1)
+	for mediaType, renderer := range renderers {
+		if renderer != nil {
+			clone[strings.ToLower(mediaType)] = renderer
+		}
+	}
+
+	return clone
+}
+
+// negotiate returns the media type and its renderer that best match the Accept header.
+// It returns the fallback when the Accept header is empty, or none of the media types is acceptable.
+func (n *contentNegotiation) negotiate(accept string) (string, Renderer) {
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return n.fallback, n.renderers[n.fallback]
+	}
+
+	best, bestQ := n.fallback, 0.0
+	for _, offer := range n.offers {
+		if q := qualityOf(ranges, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best, n.renderers[best]
+}
+
+// acceptRange represents a media range of the Accept header, e.g., `text/*;q=0.8`.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+
+	return ranges
+}
+
+// qualityOf returns the quality of the media type, given by the most specific matching media range.
+func qualityOf(ranges []acceptRange, mediaType string) float64 {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		s := -1
+		switch {
+		case r.mediaType == mediaType:
+			s = 2
+		case r.mediaType == mainType+"/*":
+			s = 1
+		case r.mediaType == "*/*":
+			s = 0
+		}
+
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+
+	return q
+}
+
+// contentTypeOf returns the content type of the media type, including the charset for the textual media types.
+func contentTypeOf(mediaType string) string {
+	if strings.HasPrefix(mediaType, "text/") || mediaType == MIMEApplicationXML {
+		return mediaType + "; " + charsetUTF8
+	}
+
+	return mediaType
+}
+
+// ErrorResponse is the value rendered by the NegotiatedErrorRenderer.
+// It is rendered as the minimal JSON response in JSON, see NewMinimalJSONResponse,
+// `<error><code>...</code><message>...</message></error>` in XML, and the message in plain text or HTML.
+type ErrorResponse struct {
+	XMLName xml.Name `json:"-" xml:"error"`
+	Code    string   `json:"code" xml:"code"`
+	Message string   `json:"message" xml:"message"`
+}
+
+func (e ErrorResponse) MarshalJSON() ([]byte, error) {
+	return NewMinimalJSONResponse(e.Code, e.Message), nil
+}
+
+func (e ErrorResponse) String() string {
+	return e.Message
+}
+
+// NegotiatedErrorRenderer renders the HTTPError as an ErrorResponse with the renderer negotiated from the request Accept header,
+// see ConfigOptions.Renderers and ConfigOptions.NegotiationFallback.
+// The error responses carry the `Vary: Accept` header.
+type NegotiatedErrorRenderer struct{}
+
+// negotiatedErrorRenderer is implemented by the error renderers whose responses vary on the request Accept header,
+// including the NegotiatedErrorRenderer given by value or by pointer.
+type negotiatedErrorRenderer interface {
+	negotiated()
+}
+
+func (NegotiatedErrorRenderer) negotiated() {}
+
+func (NegotiatedErrorRenderer) Render(c Context, e *HTTPError) ([]byte, string, error) {
+	mediaType, renderer := negotiationOf(c)
+	body, err := renderer.Render(ErrorResponse{Code: e.code(), Message: e.message()})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return body, contentTypeOf(mediaType), nil
+}
+
+// negotiationOf negotiates the media type of the response from the request Accept header, within the configured renderers.
+// It falls back to the default negotiation for the Context implementations other than the HTTP filter context.
+func negotiationOf(c Context) (string, Renderer) {
+	if fCtx, ok := c.(*context); ok {
+		return fCtx.negotiate()
+	}
+
+	return defaultContentNegotiation.negotiate("")
+}
+
+var defaultContentNegotiation = newContentNegotiation(nil, "")
@@ -0,0 +1,213 @@
+package gonvoy
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	mock_envoy "github.com/ardikabs/gonvoy/test/mock/envoy"
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentNegotiation(t *testing.T) {
+	testcases := []struct {
+		name      string
+		renderers map[string]Renderer
+		fallback  string
+		accept    string
+		expected  string
+	}{
+		{
+			name:     "empty accept uses the fallback",
+			expected: MIMEApplicationJSON,
+		},
+		{
+			name:     "exact media type",
+			accept:   "application/xml",
+			expected: MIMEApplicationXML,
+		},
+		{
+			name:     "browser accept",
+			accept:   "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			expected: MIMETextHTML,
+		},
+		{
+			name:     "quality values",
+			accept:   "text/html;q=0.5, text/plain;q=0.9",
+			expected: MIMETextPlain,
+		},
+		{
+			name:     "more specific media range wins",
+			accept:   "text/*;q=0.9, text/html;q=0.1",
+			expected: MIMETextPlain,
+		},
+		{
+			name:     "wildcard uses the fallback",
+			fallback: MIMETextPlain,
+			accept:   "*/*",
+			expected: MIMETextPlain,
+		},
+		{
+			name:     "unacceptable media types use the fallback",
+			fallback: MIMETextHTML,
+			accept:   "image/png, application/json;q=0",
+			expected: MIMETextHTML,
+		},
+		{
+			name:      "fallback is rendered even though it is not registered",
+			renderers: map[string]Renderer{MIMETextPlain: TextRenderer, MIMEApplicationXML: nil},
+			fallback:  "Application/Vnd.Api+JSON",
+			accept:    "application/xml",
+			expected:  "application/vnd.api+json",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			mediaType, renderer := newContentNegotiation(tc.renderers, tc.fallback).negotiate(tc.accept)
+			assert.Equal(t, tc.expected, mediaType)
+			assert.NotNil(t, renderer)
+		})
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	resp := ErrorResponse{Code: "NOT_FOUND", Message: "<Not Found>"}
+
+	testcases := []struct {
+		name     string
+		renderer Renderer
+		expected string
+	}{
+		{
+			name:     "XML",
+			renderer: XMLRenderer,
+			expected: `<error><code>NOT_FOUND</code><message>&lt;Not Found&gt;</message></error>`,
+		},
+		{
+			name:     "HTML",
+			renderer: HTMLRenderer,
+			expected: `<!DOCTYPE html><html><head><title>&lt;Not Found&gt;</title></head><body><h1>&lt;Not Found&gt;</h1></body></html>`,
+		},
+		{
+			name:     "plain text",
+			renderer: TextRenderer,
+			expected: `<Not Found>`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := tc.renderer.Render(resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(body))
+		})
+	}
+
+	t.Run("error response is rendered as the minimal JSON response", func(t *testing.T) {
+		body, err := JSONRenderer.Render(resp)
+		require.NoError(t, err)
+
+		var actual map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &actual))
+		assert.Equal(t, "NOT_FOUND", actual["code"])
+		assert.Equal(t, "<Not Found>", actual["message"])
+	})
+
+	t.Run("raw bytes are rendered as it is", func(t *testing.T) {
+		body, err := JSONRenderer.Render([]byte(`{"ok":true}`))
+		require.NoError(t, err)
+		assert.Equal(t, `{"ok":true}`, string(body))
+	})
+
+	t.Run("error is rendered as plain text", func(t *testing.T) {
+		body, err := TextRenderer.Render(errors.New("boom"))
+		require.NoError(t, err)
+		assert.Equal(t, "boom", string(body))
+	})
+}
+
+func TestContext_Negotiate(t *testing.T) {
+	newNegotiateContext := func(t *testing.T, accept string, cfg *internalConfig) (Context, *mock_envoy.FilterCallbackHandler, *mock_envoy.DecoderFilterCallbacks) {
+		fc := mock_envoy.NewFilterCallbackHandler(t)
+		c, err := NewContext(fc, contextOptions{config: cfg})
+		require.NoError(t, err)
+
+		reqHeaderMap := mock_envoy.NewRequestHeaderMap(t)
+		reqHeaderMap.EXPECT().Get(HeaderAccept).Return(accept, accept != "")
+		reqHeaderMap.EXPECT().Get(HeaderContentType).Return("", false).Maybe()
+
+		dcb := mock_envoy.NewDecoderFilterCallbacks(t)
+		c.(*context).reqHeaderMap = reqHeaderMap
+		c.(*context).pcb = dcb
+		return c, fc, dcb
+	}
+
+	t.Run("renders the negotiated media type", func(t *testing.T) {
+		c, _, dcb := newNegotiateContext(t, "application/xml", &internalConfig{negotiation: newContentNegotiation(nil, "")})
+		dcb.EXPECT().SendLocalReply(http.StatusNotFound, `<error><code>NOT_FOUND</code><message>Not Found</message></error>`, mock.Anything, int64(-1), mock.Anything).
+			Run(func(code int, body string, headers map[string][]string, grpcStatus int64, details string) {
+				assert.Equal(t, []string{MIMEApplicationXMLCharsetUTF8}, headers[HeaderContentType])
+				assert.Equal(t, []string{HeaderAccept}, headers[HeaderVary])
+			})
+
+		err := c.Negotiate(http.StatusNotFound, ErrorResponse{Code: "NOT_FOUND", Message: "Not Found"})
+		require.NoError(t, err)
+		assert.True(t, c.Committed())
+	})
+
+	t.Run("renders the configured fallback", func(t *testing.T) {
+		cfg := &internalConfig{negotiation: newContentNegotiation(nil, MIMETextPlain)}
+		c, _, dcb := newNegotiateContext(t, "", cfg)
+		dcb.EXPECT().SendLocalReply(http.StatusOK, "hello", mock.Anything, int64(-1), mock.Anything).
+			Run(func(code int, body string, headers map[string][]string, grpcStatus int64, details string) {
+				assert.Equal(t, []string{MIMETextPlainCharsetUTF8}, headers[HeaderContentType])
+			})
+
+		require.NoError(t, c.Negotiate(http.StatusOK, "hello"))
+	})
+
+	t.Run("failed to render", func(t *testing.T) {
+		c, _, _ := newNegotiateContext(t, "application/json", &internalConfig{})
+
+		err := c.Negotiate(http.StatusOK, func() {})
+		require.Error(t, err)
+		assert.False(t, c.Committed())
+	})
+
+	t.Run("default error handler honours the accept header", func(t *testing.T) {
+		c, fc, dcb := newNegotiateContext(t, "text/html", &internalConfig{})
+		fc.EXPECT().GetProperty(mock.Anything).Return("-", nil)
+		fc.EXPECT().Log(mock.Anything, mock.Anything).Maybe()
+		fc.EXPECT().LogLevel().Return(api.Error).Maybe()
+
+		dcb.EXPECT().SendLocalReply(http.StatusForbidden, mock.Anything, mock.Anything, int64(-1), mock.Anything).
+			Run(func(code int, body string, headers map[string][]string, grpcStatus int64, details string) {
+				assert.Contains(t, body, "<h1>Forbidden</h1>")
+				assert.Equal(t, []string{MIMETextHTMLCharsetUTF8}, headers[HeaderContentType])
+				assert.Equal(t, []string{HeaderAccept}, headers[HeaderVary])
+				assert.Equal(t, []string{"gateway"}, headers["Reporter"])
+			})
+
+		assert.Equal(t, api.LocalReply, DefaultErrorHandler(c, ErrAccessDenied))
+	})
+
+	t.Run("error renderer given by pointer varies on the accept header", func(t *testing.T) {
+		c, fc, dcb := newNegotiateContext(t, "text/html", &internalConfig{errorRenderer: &NegotiatedErrorRenderer{}})
+		fc.EXPECT().GetProperty(mock.Anything).Return("-", nil)
+		fc.EXPECT().Log(mock.Anything, mock.Anything).Maybe()
+		fc.EXPECT().LogLevel().Return(api.Error).Maybe()
+
+		dcb.EXPECT().SendLocalReply(http.StatusForbidden, mock.Anything, mock.Anything, int64(-1), mock.Anything).
+			Run(func(code int, body string, headers map[string][]string, grpcStatus int64, details string) {
+				assert.Equal(t, []string{MIMETextHTMLCharsetUTF8}, headers[HeaderContentType])
+				assert.Equal(t, []string{HeaderAccept}, headers[HeaderVary])
+			})
+
+		assert.Equal(t, api.LocalReply, DefaultErrorHandler(c, ErrAccessDenied))
+	})
+}
@@ -1,6 +1,8 @@
 package envoy
 
 import (
+	"fmt"
+
 	"github.com/ardikabs/gonvoy"
 	"github.com/envoyproxy/envoy/contrib/golang/filters/http/source/go/pkg/http"
 )
@@ -16,9 +18,43 @@ import (
 //		})
 //	}
 func RegisterHttpFilter(filterName string, fn gonvoy.HttpFilterFactoryFunc, options gonvoy.ConfigOptions) {
+	if options.FilterName == "" {
+		options.FilterName = filterName
+	}
+
 	http.RegisterHttpFilterFactoryAndConfigParser(
 		filterName,
 		gonvoy.NewHttpFilterFactory(fn),
 		gonvoy.NewConfigParser(options),
 	)
 }
+
+// RegisterTypedHttpFilter is similar to RegisterHttpFilter, except the filter is created along with its filter configuration,
+// see gonvoy.TypedHttpFilterFactoryFunc. When the ConfigOptions.FilterConfig is unset, it is defaulted to C.
+// Example usage:
+//
+//	package main
+//	func init() {
+//		RegisterTypedHttpFilter(filterName, func(cfg *UserHttpFilterConfig, deps gonvoy.Deps) gonvoy.HttpFilter {
+//			return &UserHttpFilter{config: cfg, cache: deps.Cache}
+//		}, ConfigOptions{})
+//	}
+func RegisterTypedHttpFilter[C any](filterName string, fn gonvoy.TypedHttpFilterFactoryFunc[C], options gonvoy.ConfigOptions) {
+	switch options.FilterConfig.(type) {
+	case nil:
+		options.FilterConfig = new(C)
+	case C, *C:
+	default:
+		panic(fmt.Sprintf("RegisterTypedHttpFilter: unexpected filter config type '%T', expecting '%T'", options.FilterConfig, new(C)))
+	}
+
+	if options.FilterName == "" {
+		options.FilterName = filterName
+	}
+
+	http.RegisterHttpFilterFactoryAndConfigParser(
+		filterName,
+		gonvoy.NewTypedHttpFilterFactory(fn),
+		gonvoy.NewConfigParser(options),
+	)
+}
@@ -0,0 +1,23 @@
+// Package gonvoytest provides an in-process test harness for the Gonvoy HTTP filters,
+// which drives the filter through the Envoy HTTP filter phases without Docker nor Envoy,
+// hence the filters can be tested with `go test` in milliseconds.
+//
+// Example usage:
+//
+//	h, err := gonvoytest.New(filterFactoryFunc, gonvoy.ConfigOptions{
+//		FilterConfig: new(Config),
+//	}, gonvoytest.WithFilterConfig(Config{Key: "value"}))
+//	require.NoError(t, err)
+//
+//	res, err := h.Do(httptest.NewRequest(http.MethodGet, "/", nil), upstreamHandler)
+//	require.NoError(t, err)
+//	assert.Equal(t, http.StatusOK, res.Response.StatusCode)
+//
+// The package also exports the in-memory fakes of the Envoy API types used by the harness, e.g., HeaderMap, BufferInstance,
+// FilterCallbackHandler, StreamInfo, and ConfigCallbackHandler, for unit-testing a handler directly without the harness.
+// The fakes record the filter interactions, e.g., the local replies and the metric increments, for the assertions.
+//
+// Unlike the pkg/envoy package, this package doesn't call the Envoy (C) process, hence it is safe to be used on unit-test.
+// The log messages of the filter configuration lifecycle hooks are recorded as well, see Harness.ConfigLogs,
+// unless gonvoy.ConfigOptions.ConfigLogger is given.
+package gonvoytest
@@ -0,0 +1,95 @@
+package gonvoytest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+var _ api.BufferInstance = &BufferInstance{}
+
+// BufferInstance is an in-memory fake of api.BufferInstance, the Envoy buffer given on the data phases.
+type BufferInstance struct {
+	buf []byte
+}
+
+// NewBufferInstance creates a BufferInstance holding a copy of the given bytes.
+func NewBufferInstance(b []byte) *BufferInstance {
+	return &BufferInstance{buf: append([]byte(nil), b...)}
+}
+
+func (b *BufferInstance) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+func (b *BufferInstance) WriteString(s string) (int, error) {
+	return b.Write([]byte(s))
+}
+
+func (b *BufferInstance) WriteByte(p byte) error {
+	b.buf = append(b.buf, p)
+	return nil
+}
+
+func (b *BufferInstance) WriteUint16(p uint16) error {
+	b.buf = binary.BigEndian.AppendUint16(b.buf, p)
+	return nil
+}
+
+func (b *BufferInstance) WriteUint32(p uint32) error {
+	b.buf = binary.BigEndian.AppendUint32(b.buf, p)
+	return nil
+}
+
+func (b *BufferInstance) WriteUint64(p uint64) error {
+	b.buf = binary.BigEndian.AppendUint64(b.buf, p)
+	return nil
+}
+
+func (b *BufferInstance) Bytes() []byte { return b.buf }
+
+func (b *BufferInstance) Drain(offset int) {
+	if offset > len(b.buf) {
+		offset = len(b.buf)
+	}
+
+	b.buf = b.buf[offset:]
+}
+
+func (b *BufferInstance) Len() int       { return len(b.buf) }
+func (b *BufferInstance) Reset()         { b.buf = nil }
+func (b *BufferInstance) String() string { return string(b.buf) }
+
+func (b *BufferInstance) Append(data []byte) error {
+	b.buf = append(b.buf, data...)
+	return nil
+}
+
+func (b *BufferInstance) Set(data []byte) error {
+	b.buf = append([]byte(nil), data...)
+	return nil
+}
+
+func (b *BufferInstance) SetString(s string) error {
+	return b.Set([]byte(s))
+}
+
+func (b *BufferInstance) Prepend(data []byte) error {
+	b.buf = append(append([]byte(nil), data...), b.buf...)
+	return nil
+}
+
+func (b *BufferInstance) PrependString(s string) error {
+	return b.Prepend([]byte(s))
+}
+
+func (b *BufferInstance) AppendString(s string) error {
+	return b.Append([]byte(s))
+}
+
+func newBody(b []byte) io.ReadCloser {
+	return io.NopCloser(bytes.NewReader(b))
+}
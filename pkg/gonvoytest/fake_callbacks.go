@@ -0,0 +1,331 @@
+package gonvoytest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/go-logr/logr"
+)
+
+// LocalReply represents a local reply sent by the filter through SendLocalReply.
+type LocalReply struct {
+	StatusCode int
+	Body       string
+	Headers    http.Header
+	GRPCStatus int64
+	Details    string
+}
+
+// LogEntry represents a log message sent by the filter through the filter callbacks.
+type LogEntry struct {
+	Level   api.LogType
+	Message string
+}
+
+var (
+	_ api.FilterCallbackHandler  = &FilterCallbackHandler{}
+	_ api.DecoderFilterCallbacks = &FilterCallbackHandler{}
+	_ api.EncoderFilterCallbacks = &FilterCallbackHandler{}
+)
+
+// FilterCallbackHandler is an in-memory fake of api.FilterCallbackHandler, which records the filter interactions with Envoy,
+// such as the local replies, the log messages, and the route cache clearing.
+// It serves both the decoder and the encoder filter callbacks.
+type FilterCallbackHandler struct {
+	mu                sync.Mutex
+	streamInfo        *StreamInfo
+	properties        map[string]string
+	logs              []LogEntry
+	localReplies      []LocalReply
+	continued         []api.StatusType
+	routeCacheCleared int
+
+	// resumed receives the status given to Continue or SendLocalReply,
+	// which resumes the filter chain once a phase has returned with Running status.
+	resumed chan api.StatusType
+}
+
+// NewFilterCallbackHandler creates a FilterCallbackHandler with an empty StreamInfo.
+func NewFilterCallbackHandler() *FilterCallbackHandler {
+	return &FilterCallbackHandler{
+		streamInfo: NewStreamInfo(),
+		properties: make(map[string]string),
+		resumed:    make(chan api.StatusType, 1),
+	}
+}
+
+// SetProperty sets the Envoy attribute value returned by GetProperty, e.g., `xds.route_name`.
+func (f *FilterCallbackHandler) SetProperty(name, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.properties[name] = value
+}
+
+// SetStreamInfo replaces the StreamInfo returned by StreamInfo.
+func (f *FilterCallbackHandler) SetStreamInfo(streamInfo *StreamInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.streamInfo = streamInfo
+}
+
+// LocalReplies returns the local replies sent by the filter, in order.
+func (f *FilterCallbackHandler) LocalReplies() []LocalReply {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]LocalReply(nil), f.localReplies...)
+}
+
+// LastLocalReply returns the last local reply sent by the filter, or nil if there is none.
+func (f *FilterCallbackHandler) LastLocalReply() *LocalReply {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.localReplies) == 0 {
+		return nil
+	}
+
+	reply := f.localReplies[len(f.localReplies)-1]
+	return &reply
+}
+
+// Logs returns the log messages sent by the filter, in order.
+func (f *FilterCallbackHandler) Logs() []LogEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]LogEntry(nil), f.logs...)
+}
+
+// Continued returns the statuses given to Continue, in order, i.e., by the asynchronous phases.
+func (f *FilterCallbackHandler) Continued() []api.StatusType {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]api.StatusType(nil), f.continued...)
+}
+
+// RouteCacheCleared returns how many times the route cache has been cleared by the filter.
+func (f *FilterCallbackHandler) RouteCacheCleared() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.routeCacheCleared
+}
+
+func (f *FilterCallbackHandler) StreamInfo() api.StreamInfo {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.streamInfo
+}
+
+func (f *FilterCallbackHandler) ClearRouteCache() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.routeCacheCleared++
+}
+
+func (f *FilterCallbackHandler) Log(level api.LogType, msg string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.logs = append(f.logs, LogEntry{Level: level, Message: msg})
+}
+
+func (f *FilterCallbackHandler) LogLevel() api.LogType { return api.Trace }
+
+func (f *FilterCallbackHandler) GetProperty(key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, ok := f.properties[key]
+	if !ok {
+		return "", api.ErrValueNotFound
+	}
+
+	return value, nil
+}
+
+func (f *FilterCallbackHandler) DecoderFilterCallbacks() api.DecoderFilterCallbacks { return f }
+func (f *FilterCallbackHandler) EncoderFilterCallbacks() api.EncoderFilterCallbacks { return f }
+
+func (f *FilterCallbackHandler) Continue(status api.StatusType) {
+	f.mu.Lock()
+	f.continued = append(f.continued, status)
+	f.mu.Unlock()
+
+	f.resume(status)
+}
+
+func (f *FilterCallbackHandler) SendLocalReply(responseCode int, bodyText string, headers map[string][]string, grpcStatus int64, details string) {
+	header := make(http.Header)
+	for key, values := range headers {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+
+	f.mu.Lock()
+	f.localReplies = append(f.localReplies, LocalReply{
+		StatusCode: responseCode,
+		Body:       bodyText,
+		Headers:    header,
+		GRPCStatus: grpcStatus,
+		Details:    details,
+	})
+	f.mu.Unlock()
+
+	f.resume(api.LocalReply)
+}
+
+func (f *FilterCallbackHandler) RecoverPanic() {
+	if r := recover(); r != nil {
+		f.SendLocalReply(http.StatusInternalServerError, fmt.Sprint(r), nil, -1, "go_filter_panic")
+	}
+}
+
+func (f *FilterCallbackHandler) resume(status api.StatusType) {
+	select {
+	case f.resumed <- status:
+	default:
+	}
+}
+
+// drain drops the stale resume signal, e.g., from a SendLocalReply on a synchronous phase.
+func (f *FilterCallbackHandler) drain() {
+	select {
+	case <-f.resumed:
+	default:
+	}
+}
+
+var _ api.ConfigCallbackHandler = &ConfigCallbackHandler{}
+
+// ConfigCallbackHandler is an in-memory fake of api.ConfigCallbackHandler, which records the metrics defined by the filter,
+// as well as the log messages sent through its Logger, e.g., by the filter configuration lifecycle hooks.
+type ConfigCallbackHandler struct {
+	mu       sync.Mutex
+	counters map[string]*Metric
+	gauges   map[string]*Metric
+	logs     []LogEntry
+}
+
+// NewConfigCallbackHandler creates a ConfigCallbackHandler without any metric.
+func NewConfigCallbackHandler() *ConfigCallbackHandler {
+	return &ConfigCallbackHandler{
+		counters: make(map[string]*Metric),
+		gauges:   make(map[string]*Metric),
+	}
+}
+
+func (c *ConfigCallbackHandler) DefineCounterMetric(name string) api.CounterMetric {
+	return c.define(c.counters, name)
+}
+
+func (c *ConfigCallbackHandler) DefineGaugeMetric(name string) api.GaugeMetric {
+	return c.define(c.gauges, name)
+}
+
+// Logger returns a logger that records its log messages on the ConfigCallbackHandler, see Logs.
+// It is intended to be given as the gonvoy.ConfigOptions.ConfigLogger, since the Envoy log is unavailable outside Envoy.
+func (c *ConfigCallbackHandler) Logger() logr.Logger {
+	return logr.New(&logSink{recorder: c})
+}
+
+// Log records the log message, see Logger.
+func (c *ConfigCallbackHandler) Log(level api.LogType, msg string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logs = append(c.logs, LogEntry{Level: level, Message: msg})
+}
+
+// Logs returns the recorded log messages, in order.
+func (c *ConfigCallbackHandler) Logs() []LogEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]LogEntry(nil), c.logs...)
+}
+
+// Counter returns the current value of the counter metric, or zero if it hasn't been defined.
+// The name includes the metric prefix and labels, e.g., `myfilter_requests_total_host=example.com`.
+func (c *ConfigCallbackHandler) Counter(name string) uint64 {
+	return c.value(c.counters, name)
+}
+
+// Gauge returns the current value of the gauge metric, or zero if it hasn't been defined.
+func (c *ConfigCallbackHandler) Gauge(name string) uint64 {
+	return c.value(c.gauges, name)
+}
+
+// CounterNames returns the names of the defined counter metrics, sorted.
+func (c *ConfigCallbackHandler) CounterNames() []string {
+	return c.names(c.counters)
+}
+
+// GaugeNames returns the names of the defined gauge metrics, sorted.
+func (c *ConfigCallbackHandler) GaugeNames() []string {
+	return c.names(c.gauges)
+}
+
+func (c *ConfigCallbackHandler) define(metrics map[string]*Metric, name string) *Metric {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Similar to Envoy, the same name shares the same metric.
+	m, ok := metrics[name]
+	if !ok {
+		m = &Metric{}
+		metrics[name] = m
+	}
+
+	return m
+}
+
+func (c *ConfigCallbackHandler) value(metrics map[string]*Metric, name string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := metrics[name]
+	if !ok {
+		return 0
+	}
+
+	return m.Get()
+}
+
+func (c *ConfigCallbackHandler) names(metrics map[string]*Metric) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+var (
+	_ api.CounterMetric = &Metric{}
+	_ api.GaugeMetric   = &Metric{}
+)
+
+// Metric is an in-memory fake of api.CounterMetric and api.GaugeMetric, which is safe for concurrent use.
+type Metric struct {
+	value atomic.Int64
+}
+
+func (m *Metric) Increment(offset int64) { m.value.Add(offset) }
+func (m *Metric) Get() uint64            { return uint64(m.value.Load()) }
+func (m *Metric) Record(value uint64)    { m.value.Store(int64(value)) }
@@ -0,0 +1,222 @@
+package gonvoytest
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+var (
+	_ api.RequestHeaderMap   = &HeaderMap{}
+	_ api.ResponseHeaderMap  = &HeaderMap{}
+	_ api.RequestTrailerMap  = &HeaderMap{}
+	_ api.ResponseTrailerMap = &HeaderMap{}
+)
+
+// HeaderMap is an in-memory fake of the Envoy header maps, i.e., api.RequestHeaderMap, api.ResponseHeaderMap, and the trailer maps.
+// It stores the keys in lowercase, including the pseudo-headers, e.g., `:path`, similar to Envoy.
+type HeaderMap struct {
+	mu      sync.RWMutex
+	headers map[string][]string
+}
+
+// NewHeaderMap creates a HeaderMap from the given headers, e.g., for the trailers.
+func NewHeaderMap(headers http.Header) *HeaderMap {
+	h := &HeaderMap{headers: make(map[string][]string)}
+	for key, values := range headers {
+		for _, value := range values {
+			h.Add(key, value)
+		}
+	}
+
+	return h
+}
+
+// NewRequestHeaderMap creates a HeaderMap from the given request, including the `:method`, `:path`, `:authority`, and `:scheme` pseudo-headers.
+func NewRequestHeaderMap(req *http.Request) *HeaderMap {
+	h := NewHeaderMap(req.Header)
+	h.Set(":method", req.Method)
+	h.Set(":path", req.URL.RequestURI())
+	h.Set(":authority", req.Host)
+
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	h.Set(":scheme", scheme)
+
+	if req.ContentLength > 0 {
+		h.Set("content-length", strconv.FormatInt(req.ContentLength, 10))
+	}
+
+	return h
+}
+
+// NewResponseHeaderMap creates a HeaderMap from the given response, including the `:status` pseudo-header.
+func NewResponseHeaderMap(resp *http.Response) *HeaderMap {
+	h := NewHeaderMap(resp.Header)
+	h.Set(":status", strconv.Itoa(resp.StatusCode))
+	return h
+}
+
+func (h *HeaderMap) GetRaw(name string) string {
+	v, _ := h.Get(name)
+	return v
+}
+
+func (h *HeaderMap) Get(key string) (string, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	values, ok := h.headers[strings.ToLower(key)]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+
+	return values[0], true
+}
+
+func (h *HeaderMap) Values(key string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return append([]string(nil), h.headers[strings.ToLower(key)]...)
+}
+
+func (h *HeaderMap) Set(key, value string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.headers[strings.ToLower(key)] = []string{value}
+}
+
+func (h *HeaderMap) Add(key, value string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key = strings.ToLower(key)
+	h.headers[key] = append(h.headers[key], value)
+}
+
+func (h *HeaderMap) Del(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.headers, strings.ToLower(key))
+}
+
+// Range iterates the headers in a sorted order, for a deterministic result.
+func (h *HeaderMap) Range(f func(key, value string) bool) {
+	for _, key := range h.keys() {
+		for _, value := range h.Values(key) {
+			if !f(key, value) {
+				return
+			}
+		}
+	}
+}
+
+func (h *HeaderMap) RangeWithCopy(f func(key, value string) bool) {
+	h.Range(f)
+}
+
+func (h *HeaderMap) GetAllHeaders() map[string][]string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	headers := make(map[string][]string, len(h.headers))
+	for key, values := range h.headers {
+		headers[key] = append([]string(nil), values...)
+	}
+
+	return headers
+}
+
+func (h *HeaderMap) Scheme() string { return h.GetRaw(":scheme") }
+func (h *HeaderMap) Method() string { return h.GetRaw(":method") }
+func (h *HeaderMap) Host() string   { return h.GetRaw(":authority") }
+func (h *HeaderMap) Path() string   { return h.GetRaw(":path") }
+
+func (h *HeaderMap) SetMethod(method string) { h.Set(":method", method) }
+func (h *HeaderMap) SetHost(host string)     { h.Set(":authority", host) }
+func (h *HeaderMap) SetPath(path string)     { h.Set(":path", path) }
+
+func (h *HeaderMap) Status() (int, bool) {
+	code, err := strconv.Atoi(h.GetRaw(":status"))
+	if err != nil {
+		return 0, false
+	}
+
+	return code, true
+}
+
+func (h *HeaderMap) keys() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	keys := make([]string, 0, len(h.headers))
+	for key := range h.headers {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// header returns the regular headers, excluding the pseudo-headers.
+func (h *HeaderMap) header() http.Header {
+	header := make(http.Header)
+	h.Range(func(key, value string) bool {
+		if !strings.HasPrefix(key, ":") {
+			header.Add(key, value)
+		}
+		return true
+	})
+
+	return header
+}
+
+// request builds an http.Request from the request header map and the given body.
+func (h *HeaderMap) request(body []byte) (*http.Request, error) {
+	u, err := url.ParseRequestURI(h.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	u.Scheme = h.Scheme()
+	u.Host = h.Host()
+
+	req := &http.Request{
+		Method:        h.Method(),
+		URL:           u,
+		Host:          h.Host(),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h.header(),
+		Body:          newBody(body),
+		ContentLength: int64(len(body)),
+	}
+
+	return req, nil
+}
+
+// response builds an http.Response from the response header map and the given body.
+func (h *HeaderMap) response(body []byte) *http.Response {
+	code, _ := h.Status()
+	return &http.Response{
+		Status:        strconv.Itoa(code) + " " + http.StatusText(code),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h.header(),
+		Body:          newBody(body),
+		ContentLength: int64(len(body)),
+	}
+}
@@ -0,0 +1,73 @@
+package gonvoytest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/go-logr/logr"
+)
+
+// logRecorder records the log messages, e.g., the ConfigCallbackHandler.
+type logRecorder interface {
+	Log(level api.LogType, msg string)
+}
+
+var _ logr.LogSink = &logSink{}
+
+// logSink is a logr.LogSink that records the log messages, along with their key-value pairs, e.g., `message key=value`.
+// The verbosity levels greater than zero are recorded as debug messages.
+type logSink struct {
+	recorder      logRecorder
+	name          string
+	keysAndValues []interface{}
+}
+
+func (s *logSink) Init(info logr.RuntimeInfo) {}
+func (s *logSink) Enabled(level int) bool     { return true }
+
+func (s *logSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	logType := api.Info
+	if level > 0 {
+		logType = api.Debug
+	}
+
+	s.recorder.Log(logType, s.format(msg, keysAndValues))
+}
+
+func (s *logSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.recorder.Log(api.Error, s.format(msg, append(keysAndValues, "error", err)))
+}
+
+func (s *logSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	clone := *s
+	clone.keysAndValues = append(append([]interface{}(nil), s.keysAndValues...), keysAndValues...)
+	return &clone
+}
+
+func (s *logSink) WithName(name string) logr.LogSink {
+	clone := *s
+	if clone.name != "" {
+		name = clone.name + "." + name
+	}
+
+	clone.name = name
+	return &clone
+}
+
+func (s *logSink) format(msg string, keysAndValues []interface{}) string {
+	var sb strings.Builder
+	if s.name != "" {
+		sb.WriteString(s.name)
+		sb.WriteString(": ")
+	}
+
+	sb.WriteString(msg)
+
+	kvs := append(append([]interface{}(nil), s.keysAndValues...), keysAndValues...)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		fmt.Fprintf(&sb, " %v=%v", kvs[i], kvs[i+1])
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,163 @@
+package gonvoytest
+
+import (
+	"sync"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var _ api.StreamInfo = &StreamInfo{}
+
+// StreamInfo is an in-memory fake of api.StreamInfo.
+// The stream details are set through its fields, while the dynamic metadata and the filter state actually store the values.
+type StreamInfo struct {
+	RouteName             string
+	FilterChain           string
+	HTTPProtocol          string
+	Code                  uint32
+	CodeDetails           string
+	Attempts              uint32
+	DownstreamLocal       string
+	DownstreamRemote      string
+	UpstreamLocal         string
+	UpstreamRemote        string
+	UpstreamCluster       string
+	VirtualCluster        string
+	Worker                uint32
+	DynamicMetadataValues *DynamicMetadata
+	FilterStateValues     *FilterState
+}
+
+// NewStreamInfo creates a StreamInfo with an empty dynamic metadata and filter state.
+func NewStreamInfo() *StreamInfo {
+	return &StreamInfo{
+		DynamicMetadataValues: NewDynamicMetadata(),
+		FilterStateValues:     NewFilterState(),
+	}
+}
+
+func (s *StreamInfo) GetRouteName() string    { return s.RouteName }
+func (s *StreamInfo) FilterChainName() string { return s.FilterChain }
+func (s *StreamInfo) AttemptCount() uint32    { return s.Attempts }
+func (s *StreamInfo) WorkerID() uint32        { return s.Worker }
+
+func (s *StreamInfo) Protocol() (string, bool)            { return s.HTTPProtocol, s.HTTPProtocol != "" }
+func (s *StreamInfo) ResponseCode() (uint32, bool)        { return s.Code, s.Code != 0 }
+func (s *StreamInfo) ResponseCodeDetails() (string, bool) { return s.CodeDetails, s.CodeDetails != "" }
+func (s *StreamInfo) DownstreamLocalAddress() string      { return s.DownstreamLocal }
+func (s *StreamInfo) DownstreamRemoteAddress() string     { return s.DownstreamRemote }
+func (s *StreamInfo) UpstreamLocalAddress() (string, bool) {
+	return s.UpstreamLocal, s.UpstreamLocal != ""
+}
+func (s *StreamInfo) UpstreamRemoteAddress() (string, bool) {
+	return s.UpstreamRemote, s.UpstreamRemote != ""
+}
+func (s *StreamInfo) UpstreamClusterName() (string, bool) {
+	return s.UpstreamCluster, s.UpstreamCluster != ""
+}
+func (s *StreamInfo) VirtualClusterName() (string, bool) {
+	return s.VirtualCluster, s.VirtualCluster != ""
+}
+
+func (s *StreamInfo) DynamicMetadata() api.DynamicMetadata { return s.DynamicMetadataValues }
+func (s *StreamInfo) FilterState() api.FilterState         { return s.FilterStateValues }
+
+var _ api.DynamicMetadata = &DynamicMetadata{}
+
+// DynamicMetadata is an in-memory fake of api.DynamicMetadata.
+// Similar to Envoy, the values are stored as protobuf Struct, hence it panics on an unsupported value type.
+type DynamicMetadata struct {
+	mu         sync.Mutex
+	namespaces map[string]map[string]interface{}
+}
+
+// NewDynamicMetadata creates an empty DynamicMetadata.
+func NewDynamicMetadata() *DynamicMetadata {
+	return &DynamicMetadata{namespaces: make(map[string]map[string]interface{})}
+}
+
+func (m *DynamicMetadata) Get(filterName string) map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	values, ok := m.namespaces[filterName]
+	if !ok {
+		return nil
+	}
+
+	clone := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		clone[k] = v
+	}
+
+	return clone
+}
+
+func (m *DynamicMetadata) Set(filterName string, key string, value interface{}) {
+	v, err := structpb.NewValue(value)
+	if err != nil {
+		panic(err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.namespaces[filterName]; !ok {
+		m.namespaces[filterName] = make(map[string]interface{})
+	}
+
+	m.namespaces[filterName][key] = v.AsInterface()
+}
+
+// FilterStateEntry represents a value stored within the FilterState, along with its options.
+type FilterStateEntry struct {
+	Value         string
+	StateType     api.StateType
+	LifeSpan      api.LifeSpan
+	StreamSharing api.StreamSharing
+}
+
+var _ api.FilterState = &FilterState{}
+
+// FilterState is an in-memory fake of api.FilterState.
+// Similar to Envoy, a read-only value can't be overridden.
+type FilterState struct {
+	mu      sync.Mutex
+	entries map[string]FilterStateEntry
+}
+
+// NewFilterState creates an empty FilterState.
+func NewFilterState() *FilterState {
+	return &FilterState{entries: make(map[string]FilterStateEntry)}
+}
+
+// Entry returns the stored value under the key, along with its options.
+func (s *FilterState) Entry(key string) (FilterStateEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	return entry, ok
+}
+
+func (s *FilterState) SetString(key, value string, stateType api.StateType, lifeSpan api.LifeSpan, streamSharing api.StreamSharing) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok && entry.StateType == api.StateTypeReadOnly {
+		return
+	}
+
+	s.entries[key] = FilterStateEntry{
+		Value:         value,
+		StateType:     stateType,
+		LifeSpan:      lifeSpan,
+		StreamSharing: streamSharing,
+	}
+}
+
+func (s *FilterState) GetString(key string) string {
+	entry, _ := s.Entry(key)
+	return entry.Value
+}
@@ -0,0 +1,140 @@
+package gonvoytest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderMap(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/foo?bar=baz", nil)
+	req.Header.Set("X-Foo", "bar")
+
+	h := NewRequestHeaderMap(req)
+	assert.Equal(t, http.MethodPost, h.Method())
+	assert.Equal(t, "/foo?bar=baz", h.Path())
+	assert.Equal(t, "example.com", h.Host())
+	assert.Equal(t, "http", h.Scheme())
+	assert.Equal(t, "bar", h.GetRaw("x-foo"))
+
+	h.Add("X-Foo", "qux")
+	assert.Equal(t, []string{"bar", "qux"}, h.Values("x-foo"))
+
+	h.Del("x-foo")
+	_, ok := h.Get("X-Foo")
+	assert.False(t, ok)
+
+	resp := NewResponseHeaderMap(&http.Response{StatusCode: http.StatusTeapot, Header: http.Header{}})
+	code, ok := resp.Status()
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusTeapot, code)
+}
+
+func TestBufferInstance(t *testing.T) {
+	b := NewBufferInstance([]byte("bar"))
+	require.NoError(t, b.PrependString("foo"))
+	require.NoError(t, b.AppendString("baz"))
+	assert.Equal(t, "foobarbaz", b.String())
+
+	b.Drain(3)
+	assert.Equal(t, "barbaz", b.String())
+
+	require.NoError(t, b.SetString("qux"))
+	assert.Equal(t, 3, b.Len())
+
+	b.Reset()
+	assert.Empty(t, b.Bytes())
+}
+
+func TestFilterCallbackHandler(t *testing.T) {
+	cb := NewFilterCallbackHandler()
+	cb.SetProperty("xds.route_name", "foo")
+
+	value, err := cb.GetProperty("xds.route_name")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", value)
+
+	_, err = cb.GetProperty("xds.cluster_name")
+	assert.ErrorIs(t, err, api.ErrValueNotFound)
+
+	cb.DecoderFilterCallbacks().SendLocalReply(http.StatusForbidden, "forbidden", map[string][]string{"x-foo": {"bar"}}, -1, "denied")
+	cb.EncoderFilterCallbacks().SendLocalReply(http.StatusBadGateway, "bad gateway", nil, -1, "")
+	cb.DecoderFilterCallbacks().Continue(api.Continue)
+	cb.ClearRouteCache()
+	cb.Log(api.Info, "hello")
+
+	require.Len(t, cb.LocalReplies(), 2)
+	assert.Equal(t, http.StatusForbidden, cb.LocalReplies()[0].StatusCode)
+	assert.Equal(t, "bar", cb.LocalReplies()[0].Headers.Get("x-foo"))
+	assert.Equal(t, http.StatusBadGateway, cb.LastLocalReply().StatusCode)
+	assert.Equal(t, []api.StatusType{api.Continue}, cb.Continued())
+	assert.Equal(t, 1, cb.RouteCacheCleared())
+	assert.Equal(t, []LogEntry{{Level: api.Info, Message: "hello"}}, cb.Logs())
+}
+
+func TestStreamInfo(t *testing.T) {
+	si := NewStreamInfo()
+	si.RouteName = "foo"
+	si.UpstreamCluster = "bar"
+
+	cb := NewFilterCallbackHandler()
+	cb.SetStreamInfo(si)
+
+	assert.Equal(t, "foo", cb.StreamInfo().GetRouteName())
+	cluster, ok := cb.StreamInfo().UpstreamClusterName()
+	assert.True(t, ok)
+	assert.Equal(t, "bar", cluster)
+	_, ok = cb.StreamInfo().ResponseCode()
+	assert.False(t, ok)
+
+	t.Run("dynamic metadata", func(t *testing.T) {
+		md := cb.StreamInfo().DynamicMetadata()
+		md.Set("myfilter", "count", 1)
+		md.Set("myfilter", "tags", []interface{}{"a", "b"})
+
+		assert.Equal(t, map[string]interface{}{
+			"count": float64(1),
+			"tags":  []interface{}{"a", "b"},
+		}, md.Get("myfilter"))
+		assert.Nil(t, md.Get("other"))
+
+		assert.Panics(t, func() {
+			md.Set("myfilter", "invalid", struct{}{})
+		})
+	})
+
+	t.Run("filter state", func(t *testing.T) {
+		fs := cb.StreamInfo().FilterState()
+		fs.SetString("foo", "bar", api.StateTypeReadOnly, api.LifeSpanRequest, api.SharedWithUpstreamConnection)
+		fs.SetString("foo", "baz", api.StateTypeMutable, api.LifeSpanFilterChain, api.None)
+		assert.Equal(t, "bar", fs.GetString("foo"))
+
+		entry, ok := si.FilterStateValues.Entry("foo")
+		require.True(t, ok)
+		assert.Equal(t, FilterStateEntry{
+			Value:         "bar",
+			StateType:     api.StateTypeReadOnly,
+			LifeSpan:      api.LifeSpanRequest,
+			StreamSharing: api.SharedWithUpstreamConnection,
+		}, entry)
+	})
+}
+
+func TestConfigCallbackHandler(t *testing.T) {
+	cc := NewConfigCallbackHandler()
+
+	counter := cc.DefineCounterMetric("requests_total")
+	counter.Increment(1)
+	cc.DefineCounterMetric("requests_total").Increment(2)
+	cc.DefineGaugeMetric("inflight").Record(5)
+
+	assert.Equal(t, uint64(3), cc.Counter("requests_total"))
+	assert.Equal(t, uint64(5), cc.Gauge("inflight"))
+	assert.Zero(t, cc.Counter("unknown"))
+	assert.Equal(t, []string{"requests_total"}, cc.CounterNames())
+	assert.Equal(t, []string{"inflight"}, cc.GaugeNames())
+}
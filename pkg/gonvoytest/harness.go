@@ -0,0 +1,358 @@
+package gonvoytest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/ardikabs/gonvoy"
+	xds "github.com/cncf/xds/go/xds/type/v3"
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// DefaultResumeTimeout is the default duration to wait for an asynchronous phase to resume the filter chain.
+const DefaultResumeTimeout = 5 * time.Second
+
+// Phase represents an Envoy HTTP filter phase driven by the Harness.
+type Phase string
+
+const (
+	PhaseDecodeHeaders Phase = "DecodeHeaders"
+	PhaseDecodeData    Phase = "DecodeData"
+	PhaseEncodeHeaders Phase = "EncodeHeaders"
+	PhaseEncodeData    Phase = "EncodeData"
+)
+
+// Harness drives an HTTP filter through the Envoy HTTP filter phases in-process, without Envoy,
+// i.e., DecodeHeaders, DecodeData, EncodeHeaders, EncodeData, and OnLog.
+// It is safe to run multiple requests on the same Harness, which share the same filter configuration, as in Envoy.
+type Harness struct {
+	factory         api.StreamFilterFactory
+	config          interface{}
+	configCallbacks *ConfigCallbackHandler
+	options         *HarnessOptions
+}
+
+type HarnessOptions struct {
+	filterConfig  interface{}
+	routeConfig   interface{}
+	properties    map[string]string
+	resumeTimeout time.Duration
+}
+
+type HarnessOption func(o *HarnessOptions)
+
+func NewHarnessOptions(opts ...HarnessOption) *HarnessOptions {
+	ho := &HarnessOptions{
+		properties:    make(map[string]string),
+		resumeTimeout: DefaultResumeTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(ho)
+	}
+
+	return ho
+}
+
+// WithFilterConfig sets the filter configuration, as given on the Envoy HTTP filter configuration.
+// It accepts any value that can be marshaled into a JSON object, e.g., a struct or a map.
+func WithFilterConfig(config interface{}) HarnessOption {
+	return func(o *HarnessOptions) {
+		o.filterConfig = config
+	}
+}
+
+// WithRouteConfig sets the route-level filter configuration, as given on the `typed_per_filter_config`,
+// which is merged with the filter configuration, see gonvoy.ConfigOptions.
+func WithRouteConfig(config interface{}) HarnessOption {
+	return func(o *HarnessOptions) {
+		o.routeConfig = config
+	}
+}
+
+// WithProperty sets the Envoy attribute value, e.g., `xds.route_name`, which is returned by the GetProperty.
+func WithProperty(name, value string) HarnessOption {
+	return func(o *HarnessOptions) {
+		o.properties[name] = value
+	}
+}
+
+// WithResumeTimeout sets the duration to wait for an asynchronous phase to resume the filter chain.
+func WithResumeTimeout(timeout time.Duration) HarnessOption {
+	return func(o *HarnessOptions) {
+		o.resumeTimeout = timeout
+	}
+}
+
+// New creates a Harness for the given HTTP filter, similar to how envoy.RegisterHttpFilter registers it.
+func New(filterFactoryFunc gonvoy.HttpFilterFactoryFunc, options gonvoy.ConfigOptions, opts ...HarnessOption) (*Harness, error) {
+	return newHarness(gonvoy.NewHttpFilterFactory(filterFactoryFunc), options, opts...)
+}
+
+// NewTyped creates a Harness for the given typed HTTP filter, similar to how envoy.RegisterTypedHttpFilter registers it.
+func NewTyped[C any](filterFactoryFunc gonvoy.TypedHttpFilterFactoryFunc[C], options gonvoy.ConfigOptions, opts ...HarnessOption) (*Harness, error) {
+	if options.FilterConfig == nil {
+		options.FilterConfig = new(C)
+	}
+
+	return newHarness(gonvoy.NewTypedHttpFilterFactory(filterFactoryFunc), options, opts...)
+}
+
+func newHarness(factory api.StreamFilterFactory, options gonvoy.ConfigOptions, opts ...HarnessOption) (*Harness, error) {
+	h := &Harness{
+		factory:         factory,
+		configCallbacks: NewConfigCallbackHandler(),
+		options:         NewHarnessOptions(opts...),
+	}
+
+	if options.ConfigLogger.GetSink() == nil {
+		options.ConfigLogger = h.configCallbacks.Logger()
+	}
+
+	parser := gonvoy.NewConfigParser(options)
+
+	rootAny, err := toAny(h.options.filterConfig)
+	if err != nil {
+		return nil, fmt.Errorf("gonvoytest: invalid filter config; %w", err)
+	}
+
+	h.config, err = parser.Parse(rootAny, h.configCallbacks)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.options.routeConfig != nil {
+		routeAny, err := toAny(h.options.routeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("gonvoytest: invalid route config; %w", err)
+		}
+
+		routeConfig, err := parser.Parse(routeAny, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		h.config = parser.Merge(h.config, routeConfig)
+	}
+
+	return h, nil
+}
+
+// ConfigLogs returns the log messages sent by the filter configuration lifecycle hooks, see gonvoy.ConfigContext.Log.
+// They are recorded unless gonvoy.ConfigOptions.ConfigLogger is given.
+func (h *Harness) ConfigLogs() []LogEntry {
+	return h.configCallbacks.Logs()
+}
+
+// Counter returns the current value of the counter metric, including the metric prefix, e.g., `myfilter_requests_total_host=example.com`.
+func (h *Harness) Counter(name string) uint64 {
+	return h.configCallbacks.Counter(name)
+}
+
+// Gauge returns the current value of the gauge metric, including the metric prefix.
+func (h *Harness) Gauge(name string) uint64 {
+	return h.configCallbacks.Gauge(name)
+}
+
+// Result represents the result of a request driven by the Harness.
+type Result struct {
+	// Statuses holds the status returned by every executed phase.
+	// For an asynchronous phase, it holds the status given once the filter chain is resumed.
+	Statuses map[Phase]api.StatusType
+
+	// UpstreamRequest is the request as forwarded to the upstream, including the filter modifications.
+	// It is nil when the request never reached the upstream, e.g., due to a local reply.
+	UpstreamRequest *http.Request
+
+	// Response is the response as sent to the downstream, including the filter modifications,
+	// or the local reply response.
+	Response *http.Response
+
+	// LocalReply is the local reply sent by the filter, if any.
+	LocalReply *LocalReply
+
+	// RouteCacheCleared reports how many times the route cache has been cleared by the filter.
+	RouteCacheCleared int
+
+	// Logs holds the log messages sent by the filter.
+	Logs []LogEntry
+}
+
+// Do drives the request through the filter, where the upstream serves the request once the filter lets it through.
+// When the upstream is nil, the upstream responds with 200 (OK) and an empty body.
+func (h *Harness) Do(req *http.Request, upstream http.Handler) (*Result, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("gonvoytest: failed to read request body; %w", err)
+	}
+
+	cb := NewFilterCallbackHandler()
+	for name, value := range h.options.properties {
+		cb.SetProperty(name, value)
+	}
+
+	filter := h.factory(h.config, cb)
+
+	res := &Result{Statuses: make(map[Phase]api.StatusType)}
+	defer func() {
+		filter.OnLog()
+		filter.OnDestroy(api.Normal)
+
+		res.Logs = cb.Logs()
+		res.RouteCacheCleared = cb.RouteCacheCleared()
+	}()
+
+	reqHeader := NewRequestHeaderMap(req)
+	if err := h.run(res, cb, PhaseDecodeHeaders, func() api.StatusType {
+		return filter.DecodeHeaders(reqHeader, len(reqBody) == 0)
+	}); err != nil || res.LocalReply != nil {
+		return res, err
+	}
+
+	if len(reqBody) > 0 {
+		buffer := NewBufferInstance(reqBody)
+		if err := h.run(res, cb, PhaseDecodeData, func() api.StatusType {
+			return filter.DecodeData(buffer, true)
+		}); err != nil || res.LocalReply != nil {
+			return res, err
+		}
+
+		reqBody = buffer.Bytes()
+	}
+
+	upstreamReq, err := reqHeader.request(reqBody)
+	if err != nil {
+		return res, fmt.Errorf("gonvoytest: invalid upstream request; %w", err)
+	}
+	res.UpstreamRequest = upstreamReq
+
+	resp, respBody, err := serveUpstream(upstream, upstreamReq)
+	if err != nil {
+		return res, err
+	}
+
+	respHeader := NewResponseHeaderMap(resp)
+	if err := h.run(res, cb, PhaseEncodeHeaders, func() api.StatusType {
+		return filter.EncodeHeaders(respHeader, len(respBody) == 0)
+	}); err != nil || res.LocalReply != nil {
+		return res, err
+	}
+
+	if len(respBody) > 0 {
+		buffer := NewBufferInstance(respBody)
+		if err := h.run(res, cb, PhaseEncodeData, func() api.StatusType {
+			return filter.EncodeData(buffer, true)
+		}); err != nil || res.LocalReply != nil {
+			return res, err
+		}
+
+		respBody = buffer.Bytes()
+	}
+
+	res.Response = respHeader.response(respBody)
+	return res, nil
+}
+
+// run executes the phase, and waits for the filter chain to be resumed when the phase returns with Running status.
+func (h *Harness) run(res *Result, cb *FilterCallbackHandler, phase Phase, fn func() api.StatusType) error {
+	cb.drain()
+
+	status := fn()
+	if status == api.Running {
+		select {
+		case status = <-cb.resumed:
+		case <-time.After(h.options.resumeTimeout):
+			return fmt.Errorf("gonvoytest: %s has not been resumed after %s", phase, h.options.resumeTimeout)
+		}
+	}
+
+	res.Statuses[phase] = status
+
+	if status == api.LocalReply {
+		reply := cb.LastLocalReply()
+		if reply == nil {
+			return fmt.Errorf("gonvoytest: %s returned LocalReply status without sending a local reply", phase)
+		}
+
+		res.LocalReply = reply
+		res.Response = localReplyResponse(reply)
+	}
+
+	return nil
+}
+
+func serveUpstream(upstream http.Handler, req *http.Request) (*http.Response, []byte, error) {
+	if upstream == nil {
+		upstream = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	}
+
+	rec := httptest.NewRecorder()
+	upstream.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	body, err := readBody(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gonvoytest: failed to read upstream response body; %w", err)
+	}
+
+	return resp, body, nil
+}
+
+func localReplyResponse(reply *LocalReply) *http.Response {
+	header := make(http.Header)
+	for key, values := range reply.Headers {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", reply.StatusCode, http.StatusText(reply.StatusCode)),
+		StatusCode:    reply.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          newBody([]byte(reply.Body)),
+		ContentLength: int64(len(reply.Body)),
+	}
+}
+
+func toAny(config interface{}) (*anypb.Any, error) {
+	if config == nil {
+		return &anypb.Any{}, nil
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, errors.New("config must be a JSON object")
+	}
+
+	value, err := structpb.NewStruct(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return anypb.New(&xds.TypedStruct{Value: value})
+}
+
+func readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
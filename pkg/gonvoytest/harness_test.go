@@ -0,0 +1,179 @@
+package gonvoytest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardikabs/gonvoy"
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Tenant string `json:"tenant" envoy:"mergeable"`
+}
+
+func (c *testConfig) OnConfigInit(cc gonvoy.ConfigContext) error {
+	cc.Log().Info("initialized", "tenant", c.Tenant)
+	return nil
+}
+
+type testFilter struct{}
+
+func (testFilter) OnBegin(c gonvoy.RuntimeContext, ctrl gonvoy.HttpFilterController) error {
+	cfg := c.GetFilterConfig().(*testConfig)
+	ctrl.AddHandler(&testHandler{tenant: cfg.Tenant, metrics: c.Metrics()})
+	return nil
+}
+
+func (testFilter) OnComplete(c gonvoy.Context) error { return nil }
+
+type testHandler struct {
+	gonvoy.PassthroughHttpFilterHandler
+
+	tenant  string
+	metrics gonvoy.Metrics
+}
+
+func (h *testHandler) OnRequestHeader(c gonvoy.Context) error {
+	h.metrics.Counter("requests_total", "tenant", h.tenant).Increment(1)
+
+	if c.Request().URL.Path == "/forbidden" {
+		return gonvoy.ErrAccessDenied
+	}
+
+	c.RequestHeader().Set("x-tenant", h.tenant)
+	return nil
+}
+
+func (h *testHandler) OnResponseHeader(c gonvoy.Context) error {
+	c.ResponseHeader().Set("x-filtered", "true")
+	return nil
+}
+
+func (h *testHandler) OnResponseBody(c gonvoy.Context) error {
+	_, err := c.ResponseBody().WriteString(strings.ToUpper(string(c.ResponseBody().Bytes())))
+	return err
+}
+
+func newTestHarness(t *testing.T, opts ...HarnessOption) *Harness {
+	h, err := New(func() gonvoy.HttpFilter { return testFilter{} }, gonvoy.ConfigOptions{
+		FilterConfig:            new(testConfig),
+		MetricsPrefix:           "test_",
+		DisableStrictBodyAccess: true,
+		EnableResponseBodyRead:  true,
+		EnableResponseBodyWrite: true,
+	}, opts...)
+	require.NoError(t, err)
+	return h
+}
+
+func TestHarness(t *testing.T) {
+	upstream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/octet-stream")
+		_, _ = io.WriteString(w, "hello "+r.Header.Get("x-tenant"))
+	})
+
+	t.Run("request is forwarded to the upstream", func(t *testing.T) {
+		h := newTestHarness(t, WithFilterConfig(testConfig{Tenant: "acme"}))
+
+		res, err := h.Do(httptest.NewRequest(http.MethodGet, "/foo", nil), upstream)
+		require.NoError(t, err)
+
+		assert.Nil(t, res.LocalReply)
+		assert.Equal(t, "acme", res.UpstreamRequest.Header.Get("x-tenant"))
+		assert.Equal(t, http.StatusOK, res.Response.StatusCode)
+		assert.Equal(t, "true", res.Response.Header.Get("x-filtered"))
+
+		body, err := io.ReadAll(res.Response.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "HELLO ACME", string(body))
+
+		assert.Equal(t, api.Continue, res.Statuses[PhaseDecodeHeaders])
+		assert.Equal(t, api.StopAndBuffer, res.Statuses[PhaseEncodeHeaders])
+		assert.Equal(t, api.Continue, res.Statuses[PhaseEncodeData])
+		assert.NotContains(t, res.Statuses, PhaseDecodeData)
+
+		assert.Equal(t, uint64(1), h.Counter("test_requests_total_tenant=acme"))
+	})
+
+	t.Run("config lifecycle logs are recorded", func(t *testing.T) {
+		h := newTestHarness(t, WithFilterConfig(testConfig{Tenant: "acme"}))
+
+		assert.Equal(t, []LogEntry{{Level: api.Info, Message: "config: initialized tenant=acme"}}, h.ConfigLogs())
+	})
+
+	t.Run("route config is merged", func(t *testing.T) {
+		h := newTestHarness(t, WithFilterConfig(testConfig{Tenant: "acme"}), WithRouteConfig(testConfig{Tenant: "globex"}))
+
+		res, err := h.Do(httptest.NewRequest(http.MethodGet, "/foo", nil), upstream)
+		require.NoError(t, err)
+		assert.Equal(t, "globex", res.UpstreamRequest.Header.Get("x-tenant"))
+	})
+
+	t.Run("local reply", func(t *testing.T) {
+		h := newTestHarness(t, WithFilterConfig(testConfig{Tenant: "acme"}))
+
+		res, err := h.Do(httptest.NewRequest(http.MethodGet, "/forbidden", nil), upstream)
+		require.NoError(t, err)
+
+		assert.Nil(t, res.UpstreamRequest)
+		require.NotNil(t, res.LocalReply)
+		assert.Equal(t, http.StatusForbidden, res.LocalReply.StatusCode)
+		assert.Equal(t, http.StatusForbidden, res.Response.StatusCode)
+		assert.Equal(t, api.LocalReply, res.Statuses[PhaseDecodeHeaders])
+	})
+
+	t.Run("invalid filter config", func(t *testing.T) {
+		_, err := New(func() gonvoy.HttpFilter { return testFilter{} }, gonvoy.ConfigOptions{
+			FilterConfig: new(testConfig),
+		}, WithFilterConfig([]string{"foo"}))
+		assert.ErrorContains(t, err, "gonvoytest: invalid filter config")
+	})
+
+	t.Run("request body is delivered", func(t *testing.T) {
+		h, err := New(func() gonvoy.HttpFilter { return bodyFilter{} }, gonvoy.ConfigOptions{
+			DisableStrictBodyAccess: true,
+			EnableRequestBodyRead:   true,
+			EnableRequestBodyWrite:  true,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/foo", strings.NewReader("foo"))
+		req.Header.Set("content-type", "application/octet-stream")
+		res, err := h.Do(req, nil)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(res.UpstreamRequest.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "bar", string(body))
+		assert.Equal(t, api.Continue, res.Statuses[PhaseDecodeData])
+	})
+}
+
+type bodyFilter struct{}
+
+func (bodyFilter) OnBegin(c gonvoy.RuntimeContext, ctrl gonvoy.HttpFilterController) error {
+	ctrl.AddHandler(bodyHandler{})
+	return nil
+}
+
+func (bodyFilter) OnComplete(c gonvoy.Context) error { return nil }
+
+type bodyHandler struct {
+	gonvoy.PassthroughHttpFilterHandler
+}
+
+func (bodyHandler) OnRequestBody(c gonvoy.Context) error {
+	if c.RequestBody().String() != "foo" {
+		return errors.New("unexpected request body")
+	}
+
+	_, err := c.RequestBody().WriteString("bar")
+	return err
+}
@@ -0,0 +1,137 @@
+// Code generated by mockery v2.46.1. DO NOT EDIT.
+
+package mock_envoy
+
+import (
+	api "github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DecoderFilterCallbacks is an autogenerated mock type for the DecoderFilterCallbacks type
+type DecoderFilterCallbacks struct {
+	mock.Mock
+}
+
+type DecoderFilterCallbacks_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DecoderFilterCallbacks) EXPECT() *DecoderFilterCallbacks_Expecter {
+	return &DecoderFilterCallbacks_Expecter{mock: &_m.Mock}
+}
+
+// Continue provides a mock function with given fields: _a0
+func (_m *DecoderFilterCallbacks) Continue(_a0 api.StatusType) {
+	_m.Called(_a0)
+}
+
+// DecoderFilterCallbacks_Continue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Continue'
+type DecoderFilterCallbacks_Continue_Call struct {
+	*mock.Call
+}
+
+// Continue is a helper method to define mock.On call
+//   - _a0 api.StatusType
+func (_e *DecoderFilterCallbacks_Expecter) Continue(_a0 interface{}) *DecoderFilterCallbacks_Continue_Call {
+	return &DecoderFilterCallbacks_Continue_Call{Call: _e.mock.On("Continue", _a0)}
+}
+
+func (_c *DecoderFilterCallbacks_Continue_Call) Run(run func(_a0 api.StatusType)) *DecoderFilterCallbacks_Continue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(api.StatusType))
+	})
+	return _c
+}
+
+func (_c *DecoderFilterCallbacks_Continue_Call) Return() *DecoderFilterCallbacks_Continue_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *DecoderFilterCallbacks_Continue_Call) RunAndReturn(run func(api.StatusType)) *DecoderFilterCallbacks_Continue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecoverPanic provides a mock function with given fields:
+func (_m *DecoderFilterCallbacks) RecoverPanic() {
+	_m.Called()
+}
+
+// DecoderFilterCallbacks_RecoverPanic_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecoverPanic'
+type DecoderFilterCallbacks_RecoverPanic_Call struct {
+	*mock.Call
+}
+
+// RecoverPanic is a helper method to define mock.On call
+func (_e *DecoderFilterCallbacks_Expecter) RecoverPanic() *DecoderFilterCallbacks_RecoverPanic_Call {
+	return &DecoderFilterCallbacks_RecoverPanic_Call{Call: _e.mock.On("RecoverPanic")}
+}
+
+func (_c *DecoderFilterCallbacks_RecoverPanic_Call) Run(run func()) *DecoderFilterCallbacks_RecoverPanic_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *DecoderFilterCallbacks_RecoverPanic_Call) Return() *DecoderFilterCallbacks_RecoverPanic_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *DecoderFilterCallbacks_RecoverPanic_Call) RunAndReturn(run func()) *DecoderFilterCallbacks_RecoverPanic_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendLocalReply provides a mock function with given fields: responseCode, bodyText, headers, grpcStatus, details
+func (_m *DecoderFilterCallbacks) SendLocalReply(responseCode int, bodyText string, headers map[string][]string, grpcStatus int64, details string) {
+	_m.Called(responseCode, bodyText, headers, grpcStatus, details)
+}
+
+// DecoderFilterCallbacks_SendLocalReply_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendLocalReply'
+type DecoderFilterCallbacks_SendLocalReply_Call struct {
+	*mock.Call
+}
+
+// SendLocalReply is a helper method to define mock.On call
+//   - responseCode int
+//   - bodyText string
+//   - headers map[string][]string
+//   - grpcStatus int64
+//   - details string
+func (_e *DecoderFilterCallbacks_Expecter) SendLocalReply(responseCode interface{}, bodyText interface{}, headers interface{}, grpcStatus interface{}, details interface{}) *DecoderFilterCallbacks_SendLocalReply_Call {
+	return &DecoderFilterCallbacks_SendLocalReply_Call{Call: _e.mock.On("SendLocalReply", responseCode, bodyText, headers, grpcStatus, details)}
+}
+
+func (_c *DecoderFilterCallbacks_SendLocalReply_Call) Run(run func(responseCode int, bodyText string, headers map[string][]string, grpcStatus int64, details string)) *DecoderFilterCallbacks_SendLocalReply_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int), args[1].(string), args[2].(map[string][]string), args[3].(int64), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *DecoderFilterCallbacks_SendLocalReply_Call) Return() *DecoderFilterCallbacks_SendLocalReply_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *DecoderFilterCallbacks_SendLocalReply_Call) RunAndReturn(run func(int, string, map[string][]string, int64, string)) *DecoderFilterCallbacks_SendLocalReply_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDecoderFilterCallbacks creates a new instance of DecoderFilterCallbacks. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDecoderFilterCallbacks(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DecoderFilterCallbacks {
+	mock := &DecoderFilterCallbacks{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
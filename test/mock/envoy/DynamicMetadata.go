@@ -0,0 +1,115 @@
+// Code generated by mockery v2.46.1. DO NOT EDIT.
+
+package mock_envoy
+
+import mock "github.com/stretchr/testify/mock"
+
+// DynamicMetadata is an autogenerated mock type for the DynamicMetadata type
+type DynamicMetadata struct {
+	mock.Mock
+}
+
+type DynamicMetadata_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DynamicMetadata) EXPECT() *DynamicMetadata_Expecter {
+	return &DynamicMetadata_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: filterName
+func (_m *DynamicMetadata) Get(filterName string) map[string]interface{} {
+	ret := _m.Called(filterName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func(string) map[string]interface{}); ok {
+		r0 = rf(filterName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	return r0
+}
+
+// DynamicMetadata_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type DynamicMetadata_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - filterName string
+func (_e *DynamicMetadata_Expecter) Get(filterName interface{}) *DynamicMetadata_Get_Call {
+	return &DynamicMetadata_Get_Call{Call: _e.mock.On("Get", filterName)}
+}
+
+func (_c *DynamicMetadata_Get_Call) Run(run func(filterName string)) *DynamicMetadata_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *DynamicMetadata_Get_Call) Return(_a0 map[string]interface{}) *DynamicMetadata_Get_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DynamicMetadata_Get_Call) RunAndReturn(run func(string) map[string]interface{}) *DynamicMetadata_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Set provides a mock function with given fields: filterName, key, value
+func (_m *DynamicMetadata) Set(filterName string, key string, value interface{}) {
+	_m.Called(filterName, key, value)
+}
+
+// DynamicMetadata_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
+type DynamicMetadata_Set_Call struct {
+	*mock.Call
+}
+
+// Set is a helper method to define mock.On call
+//   - filterName string
+//   - key string
+//   - value interface{}
+func (_e *DynamicMetadata_Expecter) Set(filterName interface{}, key interface{}, value interface{}) *DynamicMetadata_Set_Call {
+	return &DynamicMetadata_Set_Call{Call: _e.mock.On("Set", filterName, key, value)}
+}
+
+func (_c *DynamicMetadata_Set_Call) Run(run func(filterName string, key string, value interface{})) *DynamicMetadata_Set_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(interface{}))
+	})
+	return _c
+}
+
+func (_c *DynamicMetadata_Set_Call) Return() *DynamicMetadata_Set_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *DynamicMetadata_Set_Call) RunAndReturn(run func(string, string, interface{})) *DynamicMetadata_Set_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDynamicMetadata creates a new instance of DynamicMetadata. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDynamicMetadata(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DynamicMetadata {
+	mock := &DynamicMetadata{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
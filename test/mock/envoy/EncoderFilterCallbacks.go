@@ -0,0 +1,137 @@
+// Code generated by mockery v2.46.1. DO NOT EDIT.
+
+package mock_envoy
+
+import (
+	api "github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EncoderFilterCallbacks is an autogenerated mock type for the EncoderFilterCallbacks type
+type EncoderFilterCallbacks struct {
+	mock.Mock
+}
+
+type EncoderFilterCallbacks_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *EncoderFilterCallbacks) EXPECT() *EncoderFilterCallbacks_Expecter {
+	return &EncoderFilterCallbacks_Expecter{mock: &_m.Mock}
+}
+
+// Continue provides a mock function with given fields: _a0
+func (_m *EncoderFilterCallbacks) Continue(_a0 api.StatusType) {
+	_m.Called(_a0)
+}
+
+// EncoderFilterCallbacks_Continue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Continue'
+type EncoderFilterCallbacks_Continue_Call struct {
+	*mock.Call
+}
+
+// Continue is a helper method to define mock.On call
+//   - _a0 api.StatusType
+func (_e *EncoderFilterCallbacks_Expecter) Continue(_a0 interface{}) *EncoderFilterCallbacks_Continue_Call {
+	return &EncoderFilterCallbacks_Continue_Call{Call: _e.mock.On("Continue", _a0)}
+}
+
+func (_c *EncoderFilterCallbacks_Continue_Call) Run(run func(_a0 api.StatusType)) *EncoderFilterCallbacks_Continue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(api.StatusType))
+	})
+	return _c
+}
+
+func (_c *EncoderFilterCallbacks_Continue_Call) Return() *EncoderFilterCallbacks_Continue_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *EncoderFilterCallbacks_Continue_Call) RunAndReturn(run func(api.StatusType)) *EncoderFilterCallbacks_Continue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecoverPanic provides a mock function with given fields:
+func (_m *EncoderFilterCallbacks) RecoverPanic() {
+	_m.Called()
+}
+
+// EncoderFilterCallbacks_RecoverPanic_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecoverPanic'
+type EncoderFilterCallbacks_RecoverPanic_Call struct {
+	*mock.Call
+}
+
+// RecoverPanic is a helper method to define mock.On call
+func (_e *EncoderFilterCallbacks_Expecter) RecoverPanic() *EncoderFilterCallbacks_RecoverPanic_Call {
+	return &EncoderFilterCallbacks_RecoverPanic_Call{Call: _e.mock.On("RecoverPanic")}
+}
+
+func (_c *EncoderFilterCallbacks_RecoverPanic_Call) Run(run func()) *EncoderFilterCallbacks_RecoverPanic_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *EncoderFilterCallbacks_RecoverPanic_Call) Return() *EncoderFilterCallbacks_RecoverPanic_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *EncoderFilterCallbacks_RecoverPanic_Call) RunAndReturn(run func()) *EncoderFilterCallbacks_RecoverPanic_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendLocalReply provides a mock function with given fields: responseCode, bodyText, headers, grpcStatus, details
+func (_m *EncoderFilterCallbacks) SendLocalReply(responseCode int, bodyText string, headers map[string][]string, grpcStatus int64, details string) {
+	_m.Called(responseCode, bodyText, headers, grpcStatus, details)
+}
+
+// EncoderFilterCallbacks_SendLocalReply_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendLocalReply'
+type EncoderFilterCallbacks_SendLocalReply_Call struct {
+	*mock.Call
+}
+
+// SendLocalReply is a helper method to define mock.On call
+//   - responseCode int
+//   - bodyText string
+//   - headers map[string][]string
+//   - grpcStatus int64
+//   - details string
+func (_e *EncoderFilterCallbacks_Expecter) SendLocalReply(responseCode interface{}, bodyText interface{}, headers interface{}, grpcStatus interface{}, details interface{}) *EncoderFilterCallbacks_SendLocalReply_Call {
+	return &EncoderFilterCallbacks_SendLocalReply_Call{Call: _e.mock.On("SendLocalReply", responseCode, bodyText, headers, grpcStatus, details)}
+}
+
+func (_c *EncoderFilterCallbacks_SendLocalReply_Call) Run(run func(responseCode int, bodyText string, headers map[string][]string, grpcStatus int64, details string)) *EncoderFilterCallbacks_SendLocalReply_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int), args[1].(string), args[2].(map[string][]string), args[3].(int64), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *EncoderFilterCallbacks_SendLocalReply_Call) Return() *EncoderFilterCallbacks_SendLocalReply_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *EncoderFilterCallbacks_SendLocalReply_Call) RunAndReturn(run func(int, string, map[string][]string, int64, string)) *EncoderFilterCallbacks_SendLocalReply_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewEncoderFilterCallbacks creates a new instance of EncoderFilterCallbacks. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEncoderFilterCallbacks(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EncoderFilterCallbacks {
+	mock := &EncoderFilterCallbacks{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,118 @@
+// Code generated by mockery v2.46.1. DO NOT EDIT.
+
+package mock_envoy
+
+import (
+	api "github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FilterState is an autogenerated mock type for the FilterState type
+type FilterState struct {
+	mock.Mock
+}
+
+type FilterState_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *FilterState) EXPECT() *FilterState_Expecter {
+	return &FilterState_Expecter{mock: &_m.Mock}
+}
+
+// GetString provides a mock function with given fields: key
+func (_m *FilterState) GetString(key string) string {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetString")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// FilterState_GetString_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetString'
+type FilterState_GetString_Call struct {
+	*mock.Call
+}
+
+// GetString is a helper method to define mock.On call
+//   - key string
+func (_e *FilterState_Expecter) GetString(key interface{}) *FilterState_GetString_Call {
+	return &FilterState_GetString_Call{Call: _e.mock.On("GetString", key)}
+}
+
+func (_c *FilterState_GetString_Call) Run(run func(key string)) *FilterState_GetString_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *FilterState_GetString_Call) Return(_a0 string) *FilterState_GetString_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FilterState_GetString_Call) RunAndReturn(run func(string) string) *FilterState_GetString_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetString provides a mock function with given fields: key, value, stateType, lifeSpan, streamSharing
+func (_m *FilterState) SetString(key string, value string, stateType api.StateType, lifeSpan api.LifeSpan, streamSharing api.StreamSharing) {
+	_m.Called(key, value, stateType, lifeSpan, streamSharing)
+}
+
+// FilterState_SetString_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetString'
+type FilterState_SetString_Call struct {
+	*mock.Call
+}
+
+// SetString is a helper method to define mock.On call
+//   - key string
+//   - value string
+//   - stateType api.StateType
+//   - lifeSpan api.LifeSpan
+//   - streamSharing api.StreamSharing
+func (_e *FilterState_Expecter) SetString(key interface{}, value interface{}, stateType interface{}, lifeSpan interface{}, streamSharing interface{}) *FilterState_SetString_Call {
+	return &FilterState_SetString_Call{Call: _e.mock.On("SetString", key, value, stateType, lifeSpan, streamSharing)}
+}
+
+func (_c *FilterState_SetString_Call) Run(run func(key string, value string, stateType api.StateType, lifeSpan api.LifeSpan, streamSharing api.StreamSharing)) *FilterState_SetString_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(api.StateType), args[3].(api.LifeSpan), args[4].(api.StreamSharing))
+	})
+	return _c
+}
+
+func (_c *FilterState_SetString_Call) Return() *FilterState_SetString_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *FilterState_SetString_Call) RunAndReturn(run func(string, string, api.StateType, api.LifeSpan, api.StreamSharing)) *FilterState_SetString_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewFilterState creates a new instance of FilterState. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFilterState(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FilterState {
+	mock := &FilterState{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
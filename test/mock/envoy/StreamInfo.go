@@ -0,0 +1,784 @@
+// Code generated by mockery v2.46.1. DO NOT EDIT.
+
+package mock_envoy
+
+import (
+	api "github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StreamInfo is an autogenerated mock type for the StreamInfo type
+type StreamInfo struct {
+	mock.Mock
+}
+
+type StreamInfo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *StreamInfo) EXPECT() *StreamInfo_Expecter {
+	return &StreamInfo_Expecter{mock: &_m.Mock}
+}
+
+// AttemptCount provides a mock function with given fields:
+func (_m *StreamInfo) AttemptCount() uint32 {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttemptCount")
+	}
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// StreamInfo_AttemptCount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AttemptCount'
+type StreamInfo_AttemptCount_Call struct {
+	*mock.Call
+}
+
+// AttemptCount is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) AttemptCount() *StreamInfo_AttemptCount_Call {
+	return &StreamInfo_AttemptCount_Call{Call: _e.mock.On("AttemptCount")}
+}
+
+func (_c *StreamInfo_AttemptCount_Call) Run(run func()) *StreamInfo_AttemptCount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_AttemptCount_Call) Return(_a0 uint32) *StreamInfo_AttemptCount_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StreamInfo_AttemptCount_Call) RunAndReturn(run func() uint32) *StreamInfo_AttemptCount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DownstreamLocalAddress provides a mock function with given fields:
+func (_m *StreamInfo) DownstreamLocalAddress() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for DownstreamLocalAddress")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// StreamInfo_DownstreamLocalAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DownstreamLocalAddress'
+type StreamInfo_DownstreamLocalAddress_Call struct {
+	*mock.Call
+}
+
+// DownstreamLocalAddress is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) DownstreamLocalAddress() *StreamInfo_DownstreamLocalAddress_Call {
+	return &StreamInfo_DownstreamLocalAddress_Call{Call: _e.mock.On("DownstreamLocalAddress")}
+}
+
+func (_c *StreamInfo_DownstreamLocalAddress_Call) Run(run func()) *StreamInfo_DownstreamLocalAddress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_DownstreamLocalAddress_Call) Return(_a0 string) *StreamInfo_DownstreamLocalAddress_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StreamInfo_DownstreamLocalAddress_Call) RunAndReturn(run func() string) *StreamInfo_DownstreamLocalAddress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DownstreamRemoteAddress provides a mock function with given fields:
+func (_m *StreamInfo) DownstreamRemoteAddress() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for DownstreamRemoteAddress")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// StreamInfo_DownstreamRemoteAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DownstreamRemoteAddress'
+type StreamInfo_DownstreamRemoteAddress_Call struct {
+	*mock.Call
+}
+
+// DownstreamRemoteAddress is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) DownstreamRemoteAddress() *StreamInfo_DownstreamRemoteAddress_Call {
+	return &StreamInfo_DownstreamRemoteAddress_Call{Call: _e.mock.On("DownstreamRemoteAddress")}
+}
+
+func (_c *StreamInfo_DownstreamRemoteAddress_Call) Run(run func()) *StreamInfo_DownstreamRemoteAddress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_DownstreamRemoteAddress_Call) Return(_a0 string) *StreamInfo_DownstreamRemoteAddress_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StreamInfo_DownstreamRemoteAddress_Call) RunAndReturn(run func() string) *StreamInfo_DownstreamRemoteAddress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DynamicMetadata provides a mock function with given fields:
+func (_m *StreamInfo) DynamicMetadata() api.DynamicMetadata {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for DynamicMetadata")
+	}
+
+	var r0 api.DynamicMetadata
+	if rf, ok := ret.Get(0).(func() api.DynamicMetadata); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.DynamicMetadata)
+		}
+	}
+
+	return r0
+}
+
+// StreamInfo_DynamicMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DynamicMetadata'
+type StreamInfo_DynamicMetadata_Call struct {
+	*mock.Call
+}
+
+// DynamicMetadata is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) DynamicMetadata() *StreamInfo_DynamicMetadata_Call {
+	return &StreamInfo_DynamicMetadata_Call{Call: _e.mock.On("DynamicMetadata")}
+}
+
+func (_c *StreamInfo_DynamicMetadata_Call) Run(run func()) *StreamInfo_DynamicMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_DynamicMetadata_Call) Return(_a0 api.DynamicMetadata) *StreamInfo_DynamicMetadata_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StreamInfo_DynamicMetadata_Call) RunAndReturn(run func() api.DynamicMetadata) *StreamInfo_DynamicMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FilterChainName provides a mock function with given fields:
+func (_m *StreamInfo) FilterChainName() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for FilterChainName")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// StreamInfo_FilterChainName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FilterChainName'
+type StreamInfo_FilterChainName_Call struct {
+	*mock.Call
+}
+
+// FilterChainName is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) FilterChainName() *StreamInfo_FilterChainName_Call {
+	return &StreamInfo_FilterChainName_Call{Call: _e.mock.On("FilterChainName")}
+}
+
+func (_c *StreamInfo_FilterChainName_Call) Run(run func()) *StreamInfo_FilterChainName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_FilterChainName_Call) Return(_a0 string) *StreamInfo_FilterChainName_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StreamInfo_FilterChainName_Call) RunAndReturn(run func() string) *StreamInfo_FilterChainName_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FilterState provides a mock function with given fields:
+func (_m *StreamInfo) FilterState() api.FilterState {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for FilterState")
+	}
+
+	var r0 api.FilterState
+	if rf, ok := ret.Get(0).(func() api.FilterState); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.FilterState)
+		}
+	}
+
+	return r0
+}
+
+// StreamInfo_FilterState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FilterState'
+type StreamInfo_FilterState_Call struct {
+	*mock.Call
+}
+
+// FilterState is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) FilterState() *StreamInfo_FilterState_Call {
+	return &StreamInfo_FilterState_Call{Call: _e.mock.On("FilterState")}
+}
+
+func (_c *StreamInfo_FilterState_Call) Run(run func()) *StreamInfo_FilterState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_FilterState_Call) Return(_a0 api.FilterState) *StreamInfo_FilterState_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StreamInfo_FilterState_Call) RunAndReturn(run func() api.FilterState) *StreamInfo_FilterState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRouteName provides a mock function with given fields:
+func (_m *StreamInfo) GetRouteName() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRouteName")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// StreamInfo_GetRouteName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRouteName'
+type StreamInfo_GetRouteName_Call struct {
+	*mock.Call
+}
+
+// GetRouteName is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) GetRouteName() *StreamInfo_GetRouteName_Call {
+	return &StreamInfo_GetRouteName_Call{Call: _e.mock.On("GetRouteName")}
+}
+
+func (_c *StreamInfo_GetRouteName_Call) Run(run func()) *StreamInfo_GetRouteName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_GetRouteName_Call) Return(_a0 string) *StreamInfo_GetRouteName_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StreamInfo_GetRouteName_Call) RunAndReturn(run func() string) *StreamInfo_GetRouteName_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Protocol provides a mock function with given fields:
+func (_m *StreamInfo) Protocol() (string, bool) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Protocol")
+	}
+
+	var r0 string
+	var r1 bool
+	if rf, ok := ret.Get(0).(func() (string, bool)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// StreamInfo_Protocol_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Protocol'
+type StreamInfo_Protocol_Call struct {
+	*mock.Call
+}
+
+// Protocol is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) Protocol() *StreamInfo_Protocol_Call {
+	return &StreamInfo_Protocol_Call{Call: _e.mock.On("Protocol")}
+}
+
+func (_c *StreamInfo_Protocol_Call) Run(run func()) *StreamInfo_Protocol_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_Protocol_Call) Return(_a0 string, _a1 bool) *StreamInfo_Protocol_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StreamInfo_Protocol_Call) RunAndReturn(run func() (string, bool)) *StreamInfo_Protocol_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResponseCode provides a mock function with given fields:
+func (_m *StreamInfo) ResponseCode() (uint32, bool) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResponseCode")
+	}
+
+	var r0 uint32
+	var r1 bool
+	if rf, ok := ret.Get(0).(func() (uint32, bool)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// StreamInfo_ResponseCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResponseCode'
+type StreamInfo_ResponseCode_Call struct {
+	*mock.Call
+}
+
+// ResponseCode is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) ResponseCode() *StreamInfo_ResponseCode_Call {
+	return &StreamInfo_ResponseCode_Call{Call: _e.mock.On("ResponseCode")}
+}
+
+func (_c *StreamInfo_ResponseCode_Call) Run(run func()) *StreamInfo_ResponseCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_ResponseCode_Call) Return(_a0 uint32, _a1 bool) *StreamInfo_ResponseCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StreamInfo_ResponseCode_Call) RunAndReturn(run func() (uint32, bool)) *StreamInfo_ResponseCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResponseCodeDetails provides a mock function with given fields:
+func (_m *StreamInfo) ResponseCodeDetails() (string, bool) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResponseCodeDetails")
+	}
+
+	var r0 string
+	var r1 bool
+	if rf, ok := ret.Get(0).(func() (string, bool)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// StreamInfo_ResponseCodeDetails_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResponseCodeDetails'
+type StreamInfo_ResponseCodeDetails_Call struct {
+	*mock.Call
+}
+
+// ResponseCodeDetails is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) ResponseCodeDetails() *StreamInfo_ResponseCodeDetails_Call {
+	return &StreamInfo_ResponseCodeDetails_Call{Call: _e.mock.On("ResponseCodeDetails")}
+}
+
+func (_c *StreamInfo_ResponseCodeDetails_Call) Run(run func()) *StreamInfo_ResponseCodeDetails_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_ResponseCodeDetails_Call) Return(_a0 string, _a1 bool) *StreamInfo_ResponseCodeDetails_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StreamInfo_ResponseCodeDetails_Call) RunAndReturn(run func() (string, bool)) *StreamInfo_ResponseCodeDetails_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpstreamClusterName provides a mock function with given fields:
+func (_m *StreamInfo) UpstreamClusterName() (string, bool) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpstreamClusterName")
+	}
+
+	var r0 string
+	var r1 bool
+	if rf, ok := ret.Get(0).(func() (string, bool)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// StreamInfo_UpstreamClusterName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpstreamClusterName'
+type StreamInfo_UpstreamClusterName_Call struct {
+	*mock.Call
+}
+
+// UpstreamClusterName is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) UpstreamClusterName() *StreamInfo_UpstreamClusterName_Call {
+	return &StreamInfo_UpstreamClusterName_Call{Call: _e.mock.On("UpstreamClusterName")}
+}
+
+func (_c *StreamInfo_UpstreamClusterName_Call) Run(run func()) *StreamInfo_UpstreamClusterName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_UpstreamClusterName_Call) Return(_a0 string, _a1 bool) *StreamInfo_UpstreamClusterName_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StreamInfo_UpstreamClusterName_Call) RunAndReturn(run func() (string, bool)) *StreamInfo_UpstreamClusterName_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpstreamLocalAddress provides a mock function with given fields:
+func (_m *StreamInfo) UpstreamLocalAddress() (string, bool) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpstreamLocalAddress")
+	}
+
+	var r0 string
+	var r1 bool
+	if rf, ok := ret.Get(0).(func() (string, bool)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// StreamInfo_UpstreamLocalAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpstreamLocalAddress'
+type StreamInfo_UpstreamLocalAddress_Call struct {
+	*mock.Call
+}
+
+// UpstreamLocalAddress is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) UpstreamLocalAddress() *StreamInfo_UpstreamLocalAddress_Call {
+	return &StreamInfo_UpstreamLocalAddress_Call{Call: _e.mock.On("UpstreamLocalAddress")}
+}
+
+func (_c *StreamInfo_UpstreamLocalAddress_Call) Run(run func()) *StreamInfo_UpstreamLocalAddress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_UpstreamLocalAddress_Call) Return(_a0 string, _a1 bool) *StreamInfo_UpstreamLocalAddress_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StreamInfo_UpstreamLocalAddress_Call) RunAndReturn(run func() (string, bool)) *StreamInfo_UpstreamLocalAddress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpstreamRemoteAddress provides a mock function with given fields:
+func (_m *StreamInfo) UpstreamRemoteAddress() (string, bool) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpstreamRemoteAddress")
+	}
+
+	var r0 string
+	var r1 bool
+	if rf, ok := ret.Get(0).(func() (string, bool)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// StreamInfo_UpstreamRemoteAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpstreamRemoteAddress'
+type StreamInfo_UpstreamRemoteAddress_Call struct {
+	*mock.Call
+}
+
+// UpstreamRemoteAddress is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) UpstreamRemoteAddress() *StreamInfo_UpstreamRemoteAddress_Call {
+	return &StreamInfo_UpstreamRemoteAddress_Call{Call: _e.mock.On("UpstreamRemoteAddress")}
+}
+
+func (_c *StreamInfo_UpstreamRemoteAddress_Call) Run(run func()) *StreamInfo_UpstreamRemoteAddress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_UpstreamRemoteAddress_Call) Return(_a0 string, _a1 bool) *StreamInfo_UpstreamRemoteAddress_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StreamInfo_UpstreamRemoteAddress_Call) RunAndReturn(run func() (string, bool)) *StreamInfo_UpstreamRemoteAddress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VirtualClusterName provides a mock function with given fields:
+func (_m *StreamInfo) VirtualClusterName() (string, bool) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for VirtualClusterName")
+	}
+
+	var r0 string
+	var r1 bool
+	if rf, ok := ret.Get(0).(func() (string, bool)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// StreamInfo_VirtualClusterName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VirtualClusterName'
+type StreamInfo_VirtualClusterName_Call struct {
+	*mock.Call
+}
+
+// VirtualClusterName is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) VirtualClusterName() *StreamInfo_VirtualClusterName_Call {
+	return &StreamInfo_VirtualClusterName_Call{Call: _e.mock.On("VirtualClusterName")}
+}
+
+func (_c *StreamInfo_VirtualClusterName_Call) Run(run func()) *StreamInfo_VirtualClusterName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_VirtualClusterName_Call) Return(_a0 string, _a1 bool) *StreamInfo_VirtualClusterName_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StreamInfo_VirtualClusterName_Call) RunAndReturn(run func() (string, bool)) *StreamInfo_VirtualClusterName_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WorkerID provides a mock function with given fields:
+func (_m *StreamInfo) WorkerID() uint32 {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WorkerID")
+	}
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// StreamInfo_WorkerID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WorkerID'
+type StreamInfo_WorkerID_Call struct {
+	*mock.Call
+}
+
+// WorkerID is a helper method to define mock.On call
+func (_e *StreamInfo_Expecter) WorkerID() *StreamInfo_WorkerID_Call {
+	return &StreamInfo_WorkerID_Call{Call: _e.mock.On("WorkerID")}
+}
+
+func (_c *StreamInfo_WorkerID_Call) Run(run func()) *StreamInfo_WorkerID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *StreamInfo_WorkerID_Call) Return(_a0 uint32) *StreamInfo_WorkerID_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StreamInfo_WorkerID_Call) RunAndReturn(run func() uint32) *StreamInfo_WorkerID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStreamInfo creates a new instance of StreamInfo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStreamInfo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StreamInfo {
+	mock := &StreamInfo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,13 +3,16 @@
 package gonvoy
 
 import (
-	http "net/http"
-
 	api "github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	code "google.golang.org/genproto/googleapis/rpc/code"
+
+	http "net/http"
 
 	logr "github.com/go-logr/logr"
 
 	mock "github.com/stretchr/testify/mock"
+
+	proto "google.golang.org/protobuf/proto"
 )
 
 // MockContext is an autogenerated mock type for the Context type
@@ -25,6 +28,53 @@ func (_m *MockContext) EXPECT() *MockContext_Expecter {
 	return &MockContext_Expecter{mock: &_m.Mock}
 }
 
+// Attributes provides a mock function with given fields:
+func (_m *MockContext) Attributes() *Attributes {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Attributes")
+	}
+
+	var r0 *Attributes
+	if rf, ok := ret.Get(0).(func() *Attributes); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Attributes)
+		}
+	}
+
+	return r0
+}
+
+// MockContext_Attributes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Attributes'
+type MockContext_Attributes_Call struct {
+	*mock.Call
+}
+
+// Attributes is a helper method to define mock.On call
+func (_e *MockContext_Expecter) Attributes() *MockContext_Attributes_Call {
+	return &MockContext_Attributes_Call{Call: _e.mock.On("Attributes")}
+}
+
+func (_c *MockContext_Attributes_Call) Run(run func()) *MockContext_Attributes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContext_Attributes_Call) Return(_a0 *Attributes) *MockContext_Attributes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockContext_Attributes_Call) RunAndReturn(run func() *Attributes) *MockContext_Attributes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Committed provides a mock function with given fields:
 func (_m *MockContext) Committed() bool {
 	ret := _m.Called()
@@ -70,6 +120,209 @@ func (_c *MockContext_Committed_Call) RunAndReturn(run func() bool) *MockContext
 	return _c
 }
 
+// DynamicMetadata provides a mock function with given fields:
+func (_m *MockContext) DynamicMetadata() DynamicMetadata {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for DynamicMetadata")
+	}
+
+	var r0 DynamicMetadata
+	if rf, ok := ret.Get(0).(func() DynamicMetadata); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(DynamicMetadata)
+		}
+	}
+
+	return r0
+}
+
+// MockContext_DynamicMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DynamicMetadata'
+type MockContext_DynamicMetadata_Call struct {
+	*mock.Call
+}
+
+// DynamicMetadata is a helper method to define mock.On call
+func (_e *MockContext_Expecter) DynamicMetadata() *MockContext_DynamicMetadata_Call {
+	return &MockContext_DynamicMetadata_Call{Call: _e.mock.On("DynamicMetadata")}
+}
+
+func (_c *MockContext_DynamicMetadata_Call) Run(run func()) *MockContext_DynamicMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContext_DynamicMetadata_Call) Return(_a0 DynamicMetadata) *MockContext_DynamicMetadata_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockContext_DynamicMetadata_Call) RunAndReturn(run func() DynamicMetadata) *MockContext_DynamicMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ErrorRenderer provides a mock function with given fields:
+func (_m *MockContext) ErrorRenderer() ErrorRenderer {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ErrorRenderer")
+	}
+
+	var r0 ErrorRenderer
+	if rf, ok := ret.Get(0).(func() ErrorRenderer); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ErrorRenderer)
+		}
+	}
+
+	return r0
+}
+
+// MockContext_ErrorRenderer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ErrorRenderer'
+type MockContext_ErrorRenderer_Call struct {
+	*mock.Call
+}
+
+// ErrorRenderer is a helper method to define mock.On call
+func (_e *MockContext_Expecter) ErrorRenderer() *MockContext_ErrorRenderer_Call {
+	return &MockContext_ErrorRenderer_Call{Call: _e.mock.On("ErrorRenderer")}
+}
+
+func (_c *MockContext_ErrorRenderer_Call) Run(run func()) *MockContext_ErrorRenderer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContext_ErrorRenderer_Call) Return(_a0 ErrorRenderer) *MockContext_ErrorRenderer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockContext_ErrorRenderer_Call) RunAndReturn(run func() ErrorRenderer) *MockContext_ErrorRenderer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FilterState provides a mock function with given fields:
+func (_m *MockContext) FilterState() FilterState {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for FilterState")
+	}
+
+	var r0 FilterState
+	if rf, ok := ret.Get(0).(func() FilterState); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(FilterState)
+		}
+	}
+
+	return r0
+}
+
+// MockContext_FilterState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FilterState'
+type MockContext_FilterState_Call struct {
+	*mock.Call
+}
+
+// FilterState is a helper method to define mock.On call
+func (_e *MockContext_Expecter) FilterState() *MockContext_FilterState_Call {
+	return &MockContext_FilterState_Call{Call: _e.mock.On("FilterState")}
+}
+
+func (_c *MockContext_FilterState_Call) Run(run func()) *MockContext_FilterState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContext_FilterState_Call) Return(_a0 FilterState) *MockContext_FilterState_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockContext_FilterState_Call) RunAndReturn(run func() FilterState) *MockContext_FilterState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GRPCError provides a mock function with given fields: grpcCode, message, details
+func (_m *MockContext) GRPCError(grpcCode code.Code, message string, details ...proto.Message) error {
+	_va := make([]interface{}, len(details))
+	for _i := range details {
+		_va[_i] = details[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, grpcCode, message)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GRPCError")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(code.Code, string, ...proto.Message) error); ok {
+		r0 = rf(grpcCode, message, details...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockContext_GRPCError_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GRPCError'
+type MockContext_GRPCError_Call struct {
+	*mock.Call
+}
+
+// GRPCError is a helper method to define mock.On call
+//   - grpcCode code.Code
+//   - message string
+//   - details ...proto.Message
+func (_e *MockContext_Expecter) GRPCError(grpcCode interface{}, message interface{}, details ...interface{}) *MockContext_GRPCError_Call {
+	return &MockContext_GRPCError_Call{Call: _e.mock.On("GRPCError",
+		append([]interface{}{grpcCode, message}, details...)...)}
+}
+
+func (_c *MockContext_GRPCError_Call) Run(run func(grpcCode code.Code, message string, details ...proto.Message)) *MockContext_GRPCError_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]proto.Message, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(proto.Message)
+			}
+		}
+		run(args[0].(code.Code), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockContext_GRPCError_Call) Return(_a0 error) *MockContext_GRPCError_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockContext_GRPCError_Call) RunAndReturn(run func(code.Code, string, ...proto.Message) error) *MockContext_GRPCError_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCache provides a mock function with given fields:
 func (_m *MockContext) GetCache() Cache {
 	ret := _m.Called()
@@ -311,6 +564,51 @@ func (_c *MockContext_IsRequestBodyReadable_Call) RunAndReturn(run func() bool)
 	return _c
 }
 
+// IsRequestBodyStreaming provides a mock function with given fields:
+func (_m *MockContext) IsRequestBodyStreaming() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsRequestBodyStreaming")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockContext_IsRequestBodyStreaming_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsRequestBodyStreaming'
+type MockContext_IsRequestBodyStreaming_Call struct {
+	*mock.Call
+}
+
+// IsRequestBodyStreaming is a helper method to define mock.On call
+func (_e *MockContext_Expecter) IsRequestBodyStreaming() *MockContext_IsRequestBodyStreaming_Call {
+	return &MockContext_IsRequestBodyStreaming_Call{Call: _e.mock.On("IsRequestBodyStreaming")}
+}
+
+func (_c *MockContext_IsRequestBodyStreaming_Call) Run(run func()) *MockContext_IsRequestBodyStreaming_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContext_IsRequestBodyStreaming_Call) Return(_a0 bool) *MockContext_IsRequestBodyStreaming_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockContext_IsRequestBodyStreaming_Call) RunAndReturn(run func() bool) *MockContext_IsRequestBodyStreaming_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsRequestBodyWritable provides a mock function with given fields:
 func (_m *MockContext) IsRequestBodyWritable() bool {
 	ret := _m.Called()
@@ -446,6 +744,51 @@ func (_c *MockContext_IsResponseBodyReadable_Call) RunAndReturn(run func() bool)
 	return _c
 }
 
+// IsResponseBodyStreaming provides a mock function with given fields:
+func (_m *MockContext) IsResponseBodyStreaming() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsResponseBodyStreaming")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockContext_IsResponseBodyStreaming_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsResponseBodyStreaming'
+type MockContext_IsResponseBodyStreaming_Call struct {
+	*mock.Call
+}
+
+// IsResponseBodyStreaming is a helper method to define mock.On call
+func (_e *MockContext_Expecter) IsResponseBodyStreaming() *MockContext_IsResponseBodyStreaming_Call {
+	return &MockContext_IsResponseBodyStreaming_Call{Call: _e.mock.On("IsResponseBodyStreaming")}
+}
+
+func (_c *MockContext_IsResponseBodyStreaming_Call) Run(run func()) *MockContext_IsResponseBodyStreaming_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContext_IsResponseBodyStreaming_Call) Return(_a0 bool) *MockContext_IsResponseBodyStreaming_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockContext_IsResponseBodyStreaming_Call) RunAndReturn(run func() bool) *MockContext_IsResponseBodyStreaming_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsResponseBodyWritable provides a mock function with given fields:
 func (_m *MockContext) IsResponseBodyWritable() bool {
 	ret := _m.Called()
@@ -491,14 +834,14 @@ func (_c *MockContext_IsResponseBodyWritable_Call) RunAndReturn(run func() bool)
 	return _c
 }
 
-// JSON provides a mock function with given fields: code, b, opts
-func (_m *MockContext) JSON(code int, b []byte, opts ...LocalReplyOption) error {
+// JSON provides a mock function with given fields: _a0, b, opts
+func (_m *MockContext) JSON(_a0 int, b []byte, opts ...LocalReplyOption) error {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, code, b)
+	_ca = append(_ca, _a0, b)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
@@ -508,7 +851,7 @@ func (_m *MockContext) JSON(code int, b []byte, opts ...LocalReplyOption) error
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(int, []byte, ...LocalReplyOption) error); ok {
-		r0 = rf(code, b, opts...)
+		r0 = rf(_a0, b, opts...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -522,15 +865,15 @@ type MockContext_JSON_Call struct {
 }
 
 // JSON is a helper method to define mock.On call
-//   - code int
+//   - _a0 int
 //   - b []byte
 //   - opts ...LocalReplyOption
-func (_e *MockContext_Expecter) JSON(code interface{}, b interface{}, opts ...interface{}) *MockContext_JSON_Call {
+func (_e *MockContext_Expecter) JSON(_a0 interface{}, b interface{}, opts ...interface{}) *MockContext_JSON_Call {
 	return &MockContext_JSON_Call{Call: _e.mock.On("JSON",
-		append([]interface{}{code, b}, opts...)...)}
+		append([]interface{}{_a0, b}, opts...)...)}
 }
 
-func (_c *MockContext_JSON_Call) Run(run func(code int, b []byte, opts ...LocalReplyOption)) *MockContext_JSON_Call {
+func (_c *MockContext_JSON_Call) Run(run func(_a0 int, b []byte, opts ...LocalReplyOption)) *MockContext_JSON_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]LocalReplyOption, len(args)-2)
 		for i, a := range args[2:] {
@@ -620,6 +963,39 @@ func (_c *MockContext_LoadRequestHeaders_Call) RunAndReturn(run func(api.Request
 	return _c
 }
 
+// LoadRequestTrailers provides a mock function with given fields: _a0
+func (_m *MockContext) LoadRequestTrailers(_a0 api.RequestTrailerMap) {
+	_m.Called(_a0)
+}
+
+// MockContext_LoadRequestTrailers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoadRequestTrailers'
+type MockContext_LoadRequestTrailers_Call struct {
+	*mock.Call
+}
+
+// LoadRequestTrailers is a helper method to define mock.On call
+//   - _a0 api.RequestTrailerMap
+func (_e *MockContext_Expecter) LoadRequestTrailers(_a0 interface{}) *MockContext_LoadRequestTrailers_Call {
+	return &MockContext_LoadRequestTrailers_Call{Call: _e.mock.On("LoadRequestTrailers", _a0)}
+}
+
+func (_c *MockContext_LoadRequestTrailers_Call) Run(run func(_a0 api.RequestTrailerMap)) *MockContext_LoadRequestTrailers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(api.RequestTrailerMap))
+	})
+	return _c
+}
+
+func (_c *MockContext_LoadRequestTrailers_Call) Return() *MockContext_LoadRequestTrailers_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockContext_LoadRequestTrailers_Call) RunAndReturn(run func(api.RequestTrailerMap)) *MockContext_LoadRequestTrailers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LoadResponseBody provides a mock function with given fields: buffer, endStream
 func (_m *MockContext) LoadResponseBody(buffer api.BufferInstance, endStream bool) {
 	_m.Called(buffer, endStream)
@@ -687,6 +1063,39 @@ func (_c *MockContext_LoadResponseHeaders_Call) RunAndReturn(run func(api.Respon
 	return _c
 }
 
+// LoadResponseTrailers provides a mock function with given fields: _a0
+func (_m *MockContext) LoadResponseTrailers(_a0 api.ResponseTrailerMap) {
+	_m.Called(_a0)
+}
+
+// MockContext_LoadResponseTrailers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoadResponseTrailers'
+type MockContext_LoadResponseTrailers_Call struct {
+	*mock.Call
+}
+
+// LoadResponseTrailers is a helper method to define mock.On call
+//   - _a0 api.ResponseTrailerMap
+func (_e *MockContext_Expecter) LoadResponseTrailers(_a0 interface{}) *MockContext_LoadResponseTrailers_Call {
+	return &MockContext_LoadResponseTrailers_Call{Call: _e.mock.On("LoadResponseTrailers", _a0)}
+}
+
+func (_c *MockContext_LoadResponseTrailers_Call) Run(run func(_a0 api.ResponseTrailerMap)) *MockContext_LoadResponseTrailers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(api.ResponseTrailerMap))
+	})
+	return _c
+}
+
+func (_c *MockContext_LoadResponseTrailers_Call) Return() *MockContext_LoadResponseTrailers_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockContext_LoadResponseTrailers_Call) RunAndReturn(run func(api.ResponseTrailerMap)) *MockContext_LoadResponseTrailers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Log provides a mock function with given fields:
 func (_m *MockContext) Log() logr.Logger {
 	ret := _m.Called()
@@ -779,6 +1188,114 @@ func (_c *MockContext_Metrics_Call) RunAndReturn(run func() Metrics) *MockContex
 	return _c
 }
 
+// Negotiate provides a mock function with given fields: _a0, value, opts
+func (_m *MockContext) Negotiate(_a0 int, value interface{}, opts ...LocalReplyOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, value)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Negotiate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, interface{}, ...LocalReplyOption) error); ok {
+		r0 = rf(_a0, value, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockContext_Negotiate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Negotiate'
+type MockContext_Negotiate_Call struct {
+	*mock.Call
+}
+
+// Negotiate is a helper method to define mock.On call
+//   - _a0 int
+//   - value interface{}
+//   - opts ...LocalReplyOption
+func (_e *MockContext_Expecter) Negotiate(_a0 interface{}, value interface{}, opts ...interface{}) *MockContext_Negotiate_Call {
+	return &MockContext_Negotiate_Call{Call: _e.mock.On("Negotiate",
+		append([]interface{}{_a0, value}, opts...)...)}
+}
+
+func (_c *MockContext_Negotiate_Call) Run(run func(_a0 int, value interface{}, opts ...LocalReplyOption)) *MockContext_Negotiate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]LocalReplyOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(LocalReplyOption)
+			}
+		}
+		run(args[0].(int), args[1].(interface{}), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockContext_Negotiate_Call) Return(_a0 error) *MockContext_Negotiate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockContext_Negotiate_Call) RunAndReturn(run func(int, interface{}, ...LocalReplyOption) error) *MockContext_Negotiate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PathParam provides a mock function with given fields: name
+func (_m *MockContext) PathParam(name string) string {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PathParam")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockContext_PathParam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PathParam'
+type MockContext_PathParam_Call struct {
+	*mock.Call
+}
+
+// PathParam is a helper method to define mock.On call
+//   - name string
+func (_e *MockContext_Expecter) PathParam(name interface{}) *MockContext_PathParam_Call {
+	return &MockContext_PathParam_Call{Call: _e.mock.On("PathParam", name)}
+}
+
+func (_c *MockContext_PathParam_Call) Run(run func(name string)) *MockContext_PathParam_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockContext_PathParam_Call) Return(_a0 string) *MockContext_PathParam_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockContext_PathParam_Call) RunAndReturn(run func(string) string) *MockContext_PathParam_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ReloadRoute provides a mock function with given fields:
 func (_m *MockContext) ReloadRoute() {
 	_m.Called()
@@ -952,6 +1469,53 @@ func (_c *MockContext_RequestHeader_Call) RunAndReturn(run func() Header) *MockC
 	return _c
 }
 
+// RequestTrailer provides a mock function with given fields:
+func (_m *MockContext) RequestTrailer() Header {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestTrailer")
+	}
+
+	var r0 Header
+	if rf, ok := ret.Get(0).(func() Header); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(Header)
+		}
+	}
+
+	return r0
+}
+
+// MockContext_RequestTrailer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestTrailer'
+type MockContext_RequestTrailer_Call struct {
+	*mock.Call
+}
+
+// RequestTrailer is a helper method to define mock.On call
+func (_e *MockContext_Expecter) RequestTrailer() *MockContext_RequestTrailer_Call {
+	return &MockContext_RequestTrailer_Call{Call: _e.mock.On("RequestTrailer")}
+}
+
+func (_c *MockContext_RequestTrailer_Call) Run(run func()) *MockContext_RequestTrailer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContext_RequestTrailer_Call) Return(_a0 Header) *MockContext_RequestTrailer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockContext_RequestTrailer_Call) RunAndReturn(run func() Header) *MockContext_RequestTrailer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Response provides a mock function with given fields:
 func (_m *MockContext) Response() *http.Response {
 	ret := _m.Called()
@@ -1093,14 +1657,61 @@ func (_c *MockContext_ResponseHeader_Call) RunAndReturn(run func() Header) *Mock
 	return _c
 }
 
-// SendResponse provides a mock function with given fields: code, bodyText, opts
-func (_m *MockContext) SendResponse(code int, bodyText string, opts ...LocalReplyOption) error {
+// ResponseTrailer provides a mock function with given fields:
+func (_m *MockContext) ResponseTrailer() Header {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResponseTrailer")
+	}
+
+	var r0 Header
+	if rf, ok := ret.Get(0).(func() Header); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(Header)
+		}
+	}
+
+	return r0
+}
+
+// MockContext_ResponseTrailer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResponseTrailer'
+type MockContext_ResponseTrailer_Call struct {
+	*mock.Call
+}
+
+// ResponseTrailer is a helper method to define mock.On call
+func (_e *MockContext_Expecter) ResponseTrailer() *MockContext_ResponseTrailer_Call {
+	return &MockContext_ResponseTrailer_Call{Call: _e.mock.On("ResponseTrailer")}
+}
+
+func (_c *MockContext_ResponseTrailer_Call) Run(run func()) *MockContext_ResponseTrailer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContext_ResponseTrailer_Call) Return(_a0 Header) *MockContext_ResponseTrailer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockContext_ResponseTrailer_Call) RunAndReturn(run func() Header) *MockContext_ResponseTrailer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendResponse provides a mock function with given fields: _a0, bodyText, opts
+func (_m *MockContext) SendResponse(_a0 int, bodyText string, opts ...LocalReplyOption) error {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, code, bodyText)
+	_ca = append(_ca, _a0, bodyText)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
@@ -1110,7 +1721,7 @@ func (_m *MockContext) SendResponse(code int, bodyText string, opts ...LocalRepl
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(int, string, ...LocalReplyOption) error); ok {
-		r0 = rf(code, bodyText, opts...)
+		r0 = rf(_a0, bodyText, opts...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1124,15 +1735,15 @@ type MockContext_SendResponse_Call struct {
 }
 
 // SendResponse is a helper method to define mock.On call
-//   - code int
+//   - _a0 int
 //   - bodyText string
 //   - opts ...LocalReplyOption
-func (_e *MockContext_Expecter) SendResponse(code interface{}, bodyText interface{}, opts ...interface{}) *MockContext_SendResponse_Call {
+func (_e *MockContext_Expecter) SendResponse(_a0 interface{}, bodyText interface{}, opts ...interface{}) *MockContext_SendResponse_Call {
 	return &MockContext_SendResponse_Call{Call: _e.mock.On("SendResponse",
-		append([]interface{}{code, bodyText}, opts...)...)}
+		append([]interface{}{_a0, bodyText}, opts...)...)}
 }
 
-func (_c *MockContext_SendResponse_Call) Run(run func(code int, bodyText string, opts ...LocalReplyOption)) *MockContext_SendResponse_Call {
+func (_c *MockContext_SendResponse_Call) Run(run func(_a0 int, bodyText string, opts ...LocalReplyOption)) *MockContext_SendResponse_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]LocalReplyOption, len(args)-2)
 		for i, a := range args[2:] {
@@ -1391,14 +2002,14 @@ func (_c *MockContext_StreamInfo_Call) RunAndReturn(run func() api.StreamInfo) *
 	return _c
 }
 
-// String provides a mock function with given fields: code, s, opts
-func (_m *MockContext) String(code int, s string, opts ...LocalReplyOption) error {
+// String provides a mock function with given fields: _a0, s, opts
+func (_m *MockContext) String(_a0 int, s string, opts ...LocalReplyOption) error {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, code, s)
+	_ca = append(_ca, _a0, s)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
@@ -1408,7 +2019,7 @@ func (_m *MockContext) String(code int, s string, opts ...LocalReplyOption) erro
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(int, string, ...LocalReplyOption) error); ok {
-		r0 = rf(code, s, opts...)
+		r0 = rf(_a0, s, opts...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1422,15 +2033,15 @@ type MockContext_String_Call struct {
 }
 
 // String is a helper method to define mock.On call
-//   - code int
+//   - _a0 int
 //   - s string
 //   - opts ...LocalReplyOption
-func (_e *MockContext_Expecter) String(code interface{}, s interface{}, opts ...interface{}) *MockContext_String_Call {
+func (_e *MockContext_Expecter) String(_a0 interface{}, s interface{}, opts ...interface{}) *MockContext_String_Call {
 	return &MockContext_String_Call{Call: _e.mock.On("String",
-		append([]interface{}{code, s}, opts...)...)}
+		append([]interface{}{_a0, s}, opts...)...)}
 }
 
-func (_c *MockContext_String_Call) Run(run func(code int, s string, opts ...LocalReplyOption)) *MockContext_String_Call {
+func (_c *MockContext_String_Call) Run(run func(_a0 int, s string, opts ...LocalReplyOption)) *MockContext_String_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]LocalReplyOption, len(args)-2)
 		for i, a := range args[2:] {